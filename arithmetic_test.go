@@ -0,0 +1,63 @@
+package go6502
+
+import (
+	"testing"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// TestADCSetsOverflowOnSignedOverflow checks the classic ADC overflow cases:
+// V set when two positive operands sum to a negative result (or two
+// negatives sum to a positive one), and clear when the operands' signs
+// differ or the result's sign is unsurprising.
+func TestADCSetsOverflowOnSignedOverflow(t *testing.T) {
+	var cases = []struct {
+		name     string
+		acc, arg uint8
+		wantV    bool
+	}{
+		{"pos+pos=neg overflows", 0x50, 0x50, true},    // 80+80=160 ($A0, signed -96)
+		{"neg+neg=pos overflows", 0x80, 0xFF, true},    // -128+-1=-129, wraps to $7F (+127)
+		{"pos+neg never overflows", 0x50, 0xD0, false}, // 80 + -48, signs differ
+		{"pos+pos no overflow", 0x10, 0x10, false},     // 16+16=32, fits
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var mem flatMemory
+			mem.mem[0x0000] = 0x69 // ADC #imm
+			mem.mem[0x0001] = c.arg
+
+			var cpu = NewNMOS6502(&mem)
+			cpu.pc = 0x0000
+			cpu.acc = c.acc
+
+			cpu.Step()
+
+			if got := cpu.pf.Test(V); got != c.wantV {
+				t.Errorf("acc=$%02X + $%02X: V = %v, want %v", c.acc, c.arg, got, c.wantV)
+			}
+		})
+	}
+}
+
+// TestSBCSetsOverflowOnSignedOverflow checks the mirror case for SBC: V set
+// when subtracting a negative from a positive yields a negative result (or
+// vice versa).
+func TestSBCSetsOverflowOnSignedOverflow(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x38 // SEC
+	mem.mem[0x0001] = 0xE9 // SBC #$80
+	mem.mem[0x0002] = 0x80
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.acc = 0x7F // 127 - (-128) overflows past 127
+
+	cpu.Step() // SEC
+	cpu.Step() // SBC
+
+	if !cpu.pf.Test(V) {
+		t.Error("V not set for 127 - (-128), want set")
+	}
+}
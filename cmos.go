@@ -0,0 +1,231 @@
+package go6502
+
+import . "github.com/coopersimon/go6502/flags"
+
+// This file holds everything specific to the 65C02 (CMOS) variant: its
+// opcode table, the instructions and addressing mode it adds on top of the
+// NMOS 6502, and the handful of NMOS behaviors it fixes.
+
+// cmosOpcodes is the 256-entry dispatch table for the 65C02. It starts from
+// the NMOS table (CMOS kept almost every NMOS instruction) and then adds the
+// new opcodes and fixes.
+var cmosOpcodes = buildCMOSOpcodes()
+
+func buildCMOSOpcodes() [256]opcodeFn {
+	var t = buildNMOSOpcodes()
+
+	// BRA: branch always.
+	t[0x80] = func(cpu *CPU) { cpu.bra() }
+
+	// STZ: store zero.
+	t[0x64] = func(cpu *CPU) { cpu.stz((*CPU).zeroPage) }
+	t[0x74] = func(cpu *CPU) { cpu.stz((*CPU).zeroPageX) }
+	t[0x9C] = func(cpu *CPU) { cpu.stz((*CPU).absolute) }
+	t[0x9E] = func(cpu *CPU) { cpu.stz((*CPU).absoluteX) }
+
+	// PHX/PHY/PLX/PLY.
+	t[0xDA] = func(cpu *CPU) { cpu.phx() }
+	t[0x5A] = func(cpu *CPU) { cpu.phy() }
+	t[0xFA] = func(cpu *CPU) { cpu.plx() }
+	t[0x7A] = func(cpu *CPU) { cpu.ply() }
+
+	// TRB/TSB: test and reset/set bits.
+	t[0x14] = func(cpu *CPU) { cpu.trb((*CPU).zeroPage) }
+	t[0x1C] = func(cpu *CPU) { cpu.trb((*CPU).absolute) }
+	t[0x04] = func(cpu *CPU) { cpu.tsb((*CPU).zeroPage) }
+	t[0x0C] = func(cpu *CPU) { cpu.tsb((*CPU).absolute) }
+
+	// INC A / DEC A: the accumulator forms of the existing RMW instructions.
+	t[0x1A] = func(cpu *CPU) { cpu.inc(nil) }
+	t[0x3A] = func(cpu *CPU) { cpu.dec(nil) }
+
+	// (zp): the new indirect addressing mode, giving the instructions that
+	// already support indexedIndirect/indirectIndexed ($x1) a $x2 form with
+	// no index register.
+	t[0x12] = func(cpu *CPU) { cpu.ora((*CPU).zeroPageIndirect) }
+	t[0x32] = func(cpu *CPU) { cpu.and((*CPU).zeroPageIndirect) }
+	t[0x52] = func(cpu *CPU) { cpu.eor((*CPU).zeroPageIndirect) }
+	t[0x72] = func(cpu *CPU) { cpu.adc((*CPU).zeroPageIndirect) }
+	t[0x92] = func(cpu *CPU) { cpu.sta((*CPU).zeroPageIndirect) }
+	t[0xB2] = func(cpu *CPU) { cpu.lda((*CPU).zeroPageIndirect) }
+	t[0xD2] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).zeroPageIndirect) }
+	t[0xF2] = func(cpu *CPU) { cpu.sbc((*CPU).zeroPageIndirect) }
+
+	// Rockwell bit opcodes: RMBn/SMBn clear/set bit n of a zero page
+	// location, BBRn/BBSn branch on bit n being reset/set.
+	for bit := uint8(0); bit < 8; bit++ {
+		var b = bit
+		t[0x07+b*0x10] = func(cpu *CPU) { cpu.rmb(b) }
+		t[0x87+b*0x10] = func(cpu *CPU) { cpu.smb(b) }
+		t[0x0F+b*0x10] = func(cpu *CPU) { cpu.bbr(b) }
+		t[0x8F+b*0x10] = func(cpu *CPU) { cpu.bbs(b) }
+	}
+
+	// JMP ($xxxx) no longer wraps within the page when the pointer's low
+	// byte is $FF.
+	t[0x6C] = func(cpu *CPU) { cpu.jmpIndirectFixed() }
+
+	// Unlike NMOS, the 65C02 never jams: every reserved opcode is a
+	// documented NOP instead. $02/$22/$42/$62 are the NMOS JAM opcodes this
+	// table hasn't already turned into zero-page-indirect forms above; $EB
+	// drops the inherited NMOS SBC-duplicate behavior for the same reason.
+	for _, col := range [...]uint8{0x02, 0x22, 0x42, 0x62, 0xEB} {
+		t[col] = func(cpu *CPU) { cpu.nopImmediate() }
+	}
+
+	return t
+}
+
+// cmosCycles is the base cycle count table for the 65C02, built the same way
+// as cmosOpcodes: start from the NMOS table and override/add what's
+// different.
+var cmosCycles = buildCMOSCycles()
+
+func buildCMOSCycles() [256]uint8 {
+	var t = nmosCycles
+
+	t[0x80] = 2 // BRA
+
+	t[0x64] = 3 // STZ zp
+	t[0x74] = 4 // STZ zpX
+	t[0x9C] = 4 // STZ abs
+	t[0x9E] = 5 // STZ absX
+
+	t[0xDA] = 3 // PHX
+	t[0x5A] = 3 // PHY
+	t[0xFA] = 4 // PLX
+	t[0x7A] = 4 // PLY
+
+	t[0x14] = 5 // TRB zp
+	t[0x1C] = 6 // TRB abs
+	t[0x04] = 5 // TSB zp
+	t[0x0C] = 6 // TSB abs
+
+	t[0x1A] = 2 // INC A
+	t[0x3A] = 2 // DEC A
+
+	for _, col := range [...]uint8{0x12, 0x32, 0x52, 0x72, 0x92, 0xB2, 0xD2, 0xF2} {
+		t[col] = 5 // (zp)
+	}
+
+	for bit := uint8(0); bit < 8; bit++ {
+		t[0x07+bit*0x10] = 5 // RMBn
+		t[0x87+bit*0x10] = 5 // SMBn
+		t[0x0F+bit*0x10] = 5 // BBRn
+		t[0x8F+bit*0x10] = 5 // BBSn
+	}
+
+	// JMP ($xxxx) costs one more cycle than on NMOS.
+	t[0x6C] = 6
+
+	// $02/$22/$42/$62/$EB: 2-byte NOPs, same cost as the immediate NOPs
+	// ($82, $C2, $E2, ...) inherited from the NMOS table.
+	for _, col := range [...]uint8{0x02, 0x22, 0x42, 0x62, 0xEB} {
+		t[col] = 2
+	}
+
+	return t
+}
+
+/*** Addressing modes ***/
+
+// ($xx) — CMOS-only indirect zero page, with no index register added.
+func (cpu *CPU) zeroPageIndirect() (uint16, bool) {
+	var target = uint16(cpu.fetch())
+
+	var addrLo = cpu.memRead(target)
+	var addrHi = cpu.memRead(target + 1)
+
+	return Make16(addrHi, addrLo), false
+}
+
+/*** Instructions ***/
+
+// bra branches unconditionally.
+func (cpu *CPU) bra() {
+	cpu.branch(true)
+}
+
+// stz stores zero to memory.
+func (cpu *CPU) stz(addrMode addrModeReadFn) {
+	var addr, _ = addrMode(cpu)
+	cpu.memWrite(addr, 0)
+}
+
+func (cpu *CPU) phx() {
+	cpu.stackPush(cpu.x)
+}
+
+func (cpu *CPU) phy() {
+	cpu.stackPush(cpu.y)
+}
+
+func (cpu *CPU) plx() {
+	cpu.x = cpu.stackPop()
+	cpu.setNZ(cpu.x)
+}
+
+func (cpu *CPU) ply() {
+	cpu.y = cpu.stackPop()
+	cpu.setNZ(cpu.y)
+}
+
+// trb clears the bits of memory that are set in the accumulator, and sets Z
+// from the (pre-write) AND of the accumulator and memory.
+func (cpu *CPU) trb(addrMode addrModeReadFn) {
+	var addr, _ = addrMode(cpu)
+	var data = cpu.memRead(addr)
+
+	cpu.pf.SetIf(Z, (cpu.acc&data) == 0)
+	cpu.memWrite(addr, data&^cpu.acc)
+}
+
+// tsb sets the bits of memory that are set in the accumulator, and sets Z
+// from the (pre-write) AND of the accumulator and memory.
+func (cpu *CPU) tsb(addrMode addrModeReadFn) {
+	var addr, _ = addrMode(cpu)
+	var data = cpu.memRead(addr)
+
+	cpu.pf.SetIf(Z, (cpu.acc&data) == 0)
+	cpu.memWrite(addr, data|cpu.acc)
+}
+
+// rmb clears bit n of a zero page location.
+func (cpu *CPU) rmb(bit uint8) {
+	var addr, _ = cpu.zeroPage()
+	var data = cpu.memRead(addr)
+	cpu.memWrite(addr, data&^(1<<bit))
+}
+
+// smb sets bit n of a zero page location.
+func (cpu *CPU) smb(bit uint8) {
+	var addr, _ = cpu.zeroPage()
+	var data = cpu.memRead(addr)
+	cpu.memWrite(addr, data|(1<<bit))
+}
+
+// bbr branches if bit n of a zero page location is clear.
+func (cpu *CPU) bbr(bit uint8) {
+	var addr, _ = cpu.zeroPage()
+	var data = cpu.memRead(addr)
+	cpu.branch((data & (1 << bit)) == 0)
+}
+
+// bbs branches if bit n of a zero page location is set.
+func (cpu *CPU) bbs(bit uint8) {
+	var addr, _ = cpu.zeroPage()
+	var data = cpu.memRead(addr)
+	cpu.branch((data & (1 << bit)) != 0)
+}
+
+// jmpIndirectFixed implements JMP ($xxxx) without the NMOS page-wrap bug,
+// where incrementing the low byte of the pointer never carries into the
+// high byte.
+func (cpu *CPU) jmpIndirectFixed() {
+	var addr, _ = cpu.absolute()
+
+	var pcLo = cpu.memRead(addr)
+	var pcHi = cpu.memRead(addr + 1)
+
+	cpu.pc = Make16(pcHi, pcLo)
+}
@@ -0,0 +1,75 @@
+// Package disasm disassembles 6502 machine code into human-readable
+// instructions, for use by debugger front-ends and trace logging. Decoding
+// itself lives in the go6502 package (as Decode), since the CPU's own
+// tracing hook needs it too; this package is a thin, memorable entry point
+// for callers that only want to disassemble, not run, code.
+package disasm
+
+import (
+	"github.com/coopersimon/go6502"
+	"github.com/coopersimon/go6502/flags"
+)
+
+// Instruction is one disassembled instruction: its address, raw encoding,
+// and a rendered mnemonic/operand pair.
+type Instruction = go6502.Instruction
+
+// Disassemble decodes the single instruction at pc, reading its opcode and
+// operand bytes from bus.
+func Disassemble(bus go6502.MemoryBus, pc uint16) Instruction {
+	return go6502.Decode(bus, pc)
+}
+
+// DisassembleRange decodes every instruction starting at start up to (but
+// not including) end, walking forward by each instruction's own size. If an
+// instruction's bytes would run past end, it is still included in full.
+func DisassembleRange(bus go6502.MemoryBus, start, end uint16) []Instruction {
+	var instrs []Instruction
+
+	for pc := start; pc < end; {
+		var instr = Disassemble(bus, pc)
+		instrs = append(instrs, instr)
+		pc += uint16(instr.Size)
+	}
+
+	return instrs
+}
+
+// ByteBus adapts a flat byte slice to go6502.MemoryBus, for disassembling a
+// blob of code that isn't backed by a live bus — an object file or ROM dump
+// loaded straight into memory. Base is the address Code[0] is loaded at;
+// reads outside Code read as zero, and writes are discarded.
+type ByteBus struct {
+	Code []byte
+	Base uint16
+}
+
+// NewByteBus adapts code to a MemoryBus, as if it were loaded into memory
+// starting at base.
+func NewByteBus(code []byte, base uint16) ByteBus {
+	return ByteBus{Code: code, Base: base}
+}
+
+func (b ByteBus) Read(addr uint16) uint8 {
+	var i = int(addr) - int(b.Base)
+	if i < 0 || i >= len(b.Code) {
+		return 0
+	}
+	return b.Code[i]
+}
+
+func (b ByteBus) Write(addr uint16, data uint8) {}
+
+func (b ByteBus) Clock(cycles uint32) flags.Interrupt { return 0 }
+
+// DisassembleBytes decodes the single instruction at pc from code, as if
+// code were loaded into memory starting at base.
+func DisassembleBytes(code []byte, base, pc uint16) Instruction {
+	return Disassemble(NewByteBus(code, base), pc)
+}
+
+// DisassembleBytesRange decodes every instruction from start up to (but not
+// including) end, as if code were loaded into memory starting at base.
+func DisassembleBytesRange(code []byte, base, start, end uint16) []Instruction {
+	return DisassembleRange(NewByteBus(code, base), start, end)
+}
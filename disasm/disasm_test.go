@@ -0,0 +1,63 @@
+package disasm
+
+import "testing"
+
+// TestDisassembleBytes checks that a single instruction decodes correctly
+// from a raw byte slice loaded at a non-zero base address.
+func TestDisassembleBytes(t *testing.T) {
+	var code = []byte{0xA9, 0x42} // LDA #$42
+
+	var instr = DisassembleBytes(code, 0x8000, 0x8000)
+
+	if instr.Mnemonic != "LDA" {
+		t.Errorf("Mnemonic = %q, want %q", instr.Mnemonic, "LDA")
+	}
+	if instr.Operand != "#$42" {
+		t.Errorf("Operand = %q, want %q", instr.Operand, "#$42")
+	}
+	if instr.Size != 2 {
+		t.Errorf("Size = %d, want 2", instr.Size)
+	}
+	if instr.PC != 0x8000 {
+		t.Errorf("PC = $%04X, want $8000", instr.PC)
+	}
+}
+
+// TestDisassembleBytesRange checks that a run of instructions decodes in
+// order, each picking up where the last left off.
+func TestDisassembleBytesRange(t *testing.T) {
+	var code = []byte{
+		0xA9, 0x01, // LDA #$01
+		0xAA,       // TAX
+		0x00,       // BRK
+	}
+
+	var instrs = DisassembleBytesRange(code, 0x0400, 0x0400, 0x0400+uint16(len(code)))
+
+	if len(instrs) != 3 {
+		t.Fatalf("got %d instructions, want 3", len(instrs))
+	}
+
+	var wantMnemonics = []string{"LDA", "TAX", "BRK"}
+	for i, want := range wantMnemonics {
+		if instrs[i].Mnemonic != want {
+			t.Errorf("instrs[%d].Mnemonic = %q, want %q", i, instrs[i].Mnemonic, want)
+		}
+	}
+	if instrs[1].PC != 0x0402 {
+		t.Errorf("instrs[1].PC = $%04X, want $0402", instrs[1].PC)
+	}
+}
+
+// TestByteBusOutOfRangeReadsZero checks that ByteBus reads outside its code
+// slice return 0 rather than panicking, matching unmapped-memory behavior.
+func TestByteBusOutOfRangeReadsZero(t *testing.T) {
+	var bus = NewByteBus([]byte{0xEA}, 0x1000)
+
+	if got := bus.Read(0x0FFF); got != 0 {
+		t.Errorf("Read before base = $%02X, want $00", got)
+	}
+	if got := bus.Read(0x2000); got != 0 {
+		t.Errorf("Read past end = $%02X, want $00", got)
+	}
+}
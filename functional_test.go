@@ -0,0 +1,112 @@
+package go6502
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// flatMemory is a bare 64KB flat-addressed MemoryBus, just large enough to
+// run the Klaus2m5 functional test suites below: no mapped I/O, and Clock
+// never reports an interrupt.
+type flatMemory struct {
+	mem [0x10000]uint8
+}
+
+func (m *flatMemory) Read(addr uint16) uint8 {
+	return m.mem[addr]
+}
+
+func (m *flatMemory) Write(addr uint16, data uint8) {
+	m.mem[addr] = data
+}
+
+func (m *flatMemory) Clock(cycles uint32) Interrupt {
+	return 0
+}
+
+// runUntilStall loads path into mem and runs cpu from start until PC stops
+// advancing: every variant of the Klaus2m5 suite traps in an infinite
+// branch-to-self loop, on both success and failure, so a repeated PC is how
+// you know the run is over.
+//
+// The test binaries themselves aren't vendored in this repo (they're
+// several KB of third-party machine code); drop the named file into
+// testdata/, built from https://github.com/Klaus2m5/6502_65C02_functional_tests,
+// to exercise these tests instead of skipping them. A missing binary only
+// skips locally — with CI set, it's a hard failure, so a CI run can't pass
+// silently without ever having exercised these suites.
+func runUntilStall(t *testing.T, cpu *CPU, mem *flatMemory, path string, start uint16) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("%s not present: %v (set CI=\"\" locally to skip instead of failing)", path, err)
+		}
+		t.Skipf("%s not present: %v", path, err)
+	}
+	copy(mem.mem[:], data)
+
+	cpu.pc = start
+
+	const maxSteps = 200_000_000
+	for i := 0; i < maxSteps; i++ {
+		var pc = cpu.pc
+		cpu.Step()
+		if cpu.pc == pc {
+			return
+		}
+	}
+	t.Fatalf("did not trap within %d steps", maxSteps)
+}
+
+// TestFunctional6502 runs Klaus2m5's 6502_functional_test.bin, which
+// exercises every documented NMOS instruction and addressing mode. On
+// success it traps at $3469; any other trap address is a failure, with the
+// subtest number that failed left at $0200.
+func TestFunctional6502(t *testing.T) {
+	var mem flatMemory
+	var cpu = NewNMOS6502(&mem)
+
+	runUntilStall(t, cpu, &mem, "testdata/6502_functional_test.bin", 0x0400)
+
+	const successTrap = 0x3469
+	if cpu.pc != successTrap {
+		t.Errorf("trapped at $%04X, test number $%02X at $0200 (wanted success trap at $%04X)",
+			cpu.pc, mem.mem[0x0200], successTrap)
+	}
+}
+
+// TestDecimalMode6502 runs Klaus2m5's 6502_decimal_test.bin, which
+// exhaustively checks ADC/SBC in decimal mode against real NMOS hardware
+// behavior. Unlike the functional test, it reports its result in the zero
+// page error count at $000B rather than trapping at a fixed success
+// address.
+func TestDecimalMode6502(t *testing.T) {
+	var mem flatMemory
+	var cpu = NewNMOS6502(&mem)
+
+	runUntilStall(t, cpu, &mem, "testdata/6502_decimal_test.bin", 0x0200)
+
+	if errs := mem.mem[0x000B]; errs != 0 {
+		t.Errorf("%d decimal mode mismatches found", errs)
+	}
+}
+
+// TestFunctional65C02 runs Klaus2m5's 65C02_extended_opcodes_test.bin
+// against the CMOS variant, covering the instructions and addressing modes
+// the 65C02 adds on top of NMOS.
+func TestFunctional65C02(t *testing.T) {
+	var mem flatMemory
+	var cpu = NewCMOS65C02(&mem)
+
+	runUntilStall(t, cpu, &mem, "testdata/65C02_extended_opcodes_test.bin", 0x0400)
+
+	const successTrap = 0x24F1
+	if cpu.pc != successTrap {
+		t.Errorf("trapped at $%04X, test number $%02X at $0200 (wanted success trap at $%04X)",
+			cpu.pc, mem.mem[0x0200], successTrap)
+	}
+}
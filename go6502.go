@@ -1,7 +1,10 @@
 package go6502
 
-// A cpu method that gets the address.
-type addrModeReadFn = func(cpu *CPU) uint16
+import . "github.com/coopersimon/go6502/flags"
+
+// A cpu method that gets the address, and whether computing it crossed a
+// page boundary (relevant for the "+1 if page crossed" cycle penalty).
+type addrModeReadFn = func(cpu *CPU) (uint16, bool)
 
 // MemoryBus
 type MemoryBus interface {
@@ -10,6 +13,9 @@ type MemoryBus interface {
 	Clock(cycles uint32) Interrupt
 }
 
+// A cpu instruction, dispatched from the 256-entry opcode table.
+type opcodeFn = func(cpu *CPU)
+
 // CPU: 6502 CPU
 type CPU struct {
 	// Registers
@@ -27,54 +33,203 @@ type CPU struct {
 	halt    bool
 	intMask Interrupt
 	cycles  uint32
+
+	// Timing
+	totalCycles uint64      // Running total, for callers that want to track elapsed time
+	cycleTable  *[256]uint8 // Base cycle counts for this variant
+
+	// Variant
+	opcodes    *[256]opcodeFn   // Opcode dispatch table for this variant
+	opcodeInfo *[256]OpcodeInfo // Opcode metadata for this variant, shared with Decode
+	cmos       bool             // true for 65C02 (CMOS), false for the original NMOS 6502
+
+	// illegalOpcodes selects whether the undocumented NMOS opcodes (LAX,
+	// SAX, SLO, ...) behave as documented by real hardware; when false they
+	// execute as NOPs with the correct byte count instead (the JAM opcodes
+	// halt the CPU either way). Defaults to true for NMOS and false for CMOS,
+	// since the 65C02 doesn't implement them.
+	illegalOpcodes bool
+
+	// Debugging
+	tracer      Tracer
+	breakpoints map[uint16]struct{}
+	watchpoints map[uint16]WatchKind
+	watchHit    bool
 }
 
+// New constructs an NMOS 6502 CPU.
+//
+// Deprecated: use NewNMOS6502 or NewCMOS65C02 to be explicit about the variant.
 func New(memoryBus MemoryBus) *CPU {
+	return NewNMOS6502(memoryBus)
+}
+
+// NewNMOS6502 constructs a CPU modelling the original NMOS 6502.
+func NewNMOS6502(memoryBus MemoryBus) *CPU {
 	return &CPU{
-		0,
-		0,
-		0,
-		0xFF,
-		0xFFFE,
-		0,
+		acc: 0,
+		x:   0,
+		y:   0,
+		sp:  0xFF,
+		pc:  0xFFFE,
+		pf:  0,
 
-		memoryBus,
+		memBus: memoryBus,
+
+		halt:    false,
+		intMask: 0,
+		cycles:  0,
+
+		opcodes:    &nmosOpcodes,
+		opcodeInfo: &NMOSOpcodeInfo,
+		cmos:       false,
+		cycleTable: &nmosCycles,
+
+		illegalOpcodes: true,
+	}
+}
 
-		false,
-		0,
-		0,
+// NewCMOS65C02 constructs a CPU modelling the 65C02 (CMOS) variant. It shares
+// the NMOS instruction implementations but uses its own opcode table, adding
+// new instructions and addressing modes and fixing a handful of NMOS quirks.
+func NewCMOS65C02(memoryBus MemoryBus) *CPU {
+	return &CPU{
+		acc: 0,
+		x:   0,
+		y:   0,
+		sp:  0xFF,
+		pc:  0xFFFE,
+		pf:  0,
+
+		memBus: memoryBus,
+
+		halt:    false,
+		intMask: 0,
+		cycles:  0,
+
+		opcodes:    &cmosOpcodes,
+		opcodeInfo: &CMOSOpcodeInfo,
+		cmos:       true,
+		cycleTable: &cmosCycles,
 	}
 }
 
-// Step a single instruction and clock the memory bus.
-func (cpu *CPU) Step() uint32 {
-	if cpu.intMask != 0 {
+// Step a single instruction and clock the memory bus. The returned
+// StopReason tells a debugging frontend why it should stop running: a
+// breakpoint at the instruction about to execute, a watchpoint the
+// instruction just hit, the CPU being halted, or nothing notable (normal).
+func (cpu *CPU) Step() (uint32, StopReason) {
+	if _, atBreakpoint := cpu.breakpoints[cpu.pc]; atBreakpoint {
+		return 0, StopBreakpoint
+	}
+
+	var tracing = cpu.tracer != nil
+	var pre StateSnapshot
+	var instr Instruction
+	if tracing {
+		pre = cpu.snapshot()
+		instr = cpu.decode(cpu.pc)
+	}
+
+	cpu.watchHit = false
+
+	var reason = StopNormal
+	if cpu.halt {
+		reason = StopHalt
+	} else if cpu.intMask != 0 {
 		cpu.handleInterrupt()
-	} else if !cpu.halt {
+	} else {
 		cpu.executeInstruction()
 	}
 
 	var cycles = cpu.cycles
 	cpu.cycles = 0
+	cpu.totalCycles += uint64(cycles)
 	cpu.intMask.Set(cpu.memBus.Clock(cycles))
-	return cycles
+
+	if tracing {
+		cpu.tracer.OnStep(pre, cpu.snapshot(), instr)
+	}
+
+	if reason == StopNormal && cpu.watchHit {
+		reason = StopWatchpoint
+	}
+
+	return cycles, reason
+}
+
+func (cpu *CPU) decode(pc uint16) Instruction {
+	return decodeWith(cpu.opcodeInfo, cpu.memBus, pc)
+}
+
+// Cycles returns the running total of cycles clocked since the CPU was
+// constructed.
+func (cpu *CPU) Cycles() uint64 {
+	return cpu.totalCycles
+}
+
+// SetIllegalOpcodes controls whether the undocumented NMOS opcodes behave as
+// real hardware does (the default for NewNMOS6502) or as same-byte-count
+// NOPs (the default for NewCMOS65C02, which doesn't implement them).
+func (cpu *CPU) SetIllegalOpcodes(enabled bool) {
+	cpu.illegalOpcodes = enabled
+}
+
+// Reset performs a power-on/reset sequence: it sets SP to 0xFD, sets the I
+// flag, loads PC from the reset vector at $FFFC/$FFFD, and clears a JAM
+// opcode's halt, the one thing real hardware's reset line can undo. It costs
+// 7 cycles, the same as BRK and a hardware interrupt.
+//
+// Real NMOS hardware leaves D in whatever state it was already in across a
+// reset, since reset doesn't touch it; this emulator clears it instead, so
+// a reset always starts from the same, documented state rather than
+// whatever D a caller happened to leave set.
+func (cpu *CPU) Reset() {
+	cpu.sp = 0xFD
+	cpu.pf.Set(I)
+	cpu.pf.Clear(D)
+	cpu.halt = false
+
+	var pcLo = cpu.memRead(0xFFFC)
+	var pcHi = cpu.memRead(0xFFFD)
+	cpu.pc = Make16(pcHi, pcLo)
+
+	cpu.totalCycles += 7
+	cpu.intMask.Set(cpu.memBus.Clock(7))
+}
+
+// TriggerIRQ asserts the IRQ line from outside, for bus implementations
+// that would rather call this directly than return an Interrupt bitmask
+// from Clock. The two approaches compose: Step still honors whatever Clock
+// returns as well.
+func (cpu *CPU) TriggerIRQ() {
+	cpu.intMask.Set(IRQ)
+}
+
+// TriggerNMI asserts the NMI line from outside. See TriggerIRQ.
+func (cpu *CPU) TriggerNMI() {
+	cpu.intMask.Set(NMI)
 }
 
 /*** INTERNAL ***/
 
 func (cpu *CPU) handleInterrupt() {
 	if cpu.intMask.Test(NMI) {
-		cpu.interruptRoutine(NMI, 0xFFFA)
+		cpu.interruptRoutine(NMI, 0xFFFA, cpu.pf)
 	} else if cpu.intMask.Test(IRQ) && !cpu.pf.Test(I) {
-		cpu.interruptRoutine(IRQ, 0xFFFE)
+		cpu.interruptRoutine(IRQ, 0xFFFE, cpu.pf)
 	}
 }
 
-func (cpu *CPU) interruptRoutine(intFlag Interrupt, vector uint16) {
+// interruptRoutine pushes PC and pushedFlags, jumps through vector, and
+// sets I. pushedFlags is taken as a parameter rather than always reading
+// cpu.pf so that brk() can push a B-flagged copy without the B flag ever
+// existing in the live, tested flags.
+func (cpu *CPU) interruptRoutine(intFlag Interrupt, vector uint16, pushedFlags ProgramFlags) {
 	cpu.intMask.Clear(intFlag)
 	cpu.stackPush(Hi(cpu.pc))
 	cpu.stackPush(Lo(cpu.pc))
-	cpu.stackPush(uint8(cpu.pf))
+	cpu.stackPush(uint8(pushedFlags))
 
 	cpu.pf.Set(I)
 
@@ -82,360 +237,392 @@ func (cpu *CPU) interruptRoutine(intFlag Interrupt, vector uint16) {
 	var pcHi = cpu.memRead(vector + 1)
 
 	cpu.pc = Make16(pcHi, pcLo)
+
+	// A hardware interrupt takes 7 cycles, the same as BRK, regardless of
+	// variant.
+	cpu.cycles = 7
 }
 
 func (cpu *CPU) executeInstruction() {
 	var instr = cpu.fetch()
+	cpu.cycles = uint32(cpu.cycleTable[instr])
+	cpu.opcodes[instr](cpu)
+}
 
-	switch instr {
-	case 0x00:
-		cpu.brk()
-
-	case 0x01:
-		cpu.ora((*CPU).indexedIndirect)
-	case 0x11:
-		cpu.ora((*CPU).indirectIndexed)
-	case 0x05:
-		cpu.ora((*CPU).zeroPage)
-	case 0x15:
-		cpu.ora((*CPU).zeroPageX)
-	case 0x09:
-		cpu.ora(nil)
-	case 0x19:
-		cpu.ora((*CPU).absoluteY)
-	case 0x0D:
-		cpu.ora((*CPU).absolute)
-	case 0x1D:
-		cpu.ora((*CPU).absoluteX)
-
-	case 0x21:
-		cpu.and((*CPU).indexedIndirect)
-	case 0x31:
-		cpu.and((*CPU).indirectIndexed)
-	case 0x25:
-		cpu.and((*CPU).zeroPage)
-	case 0x35:
-		cpu.and((*CPU).zeroPageX)
-	case 0x29:
-		cpu.and(nil)
-	case 0x39:
-		cpu.and((*CPU).absoluteY)
-	case 0x2D:
-		cpu.and((*CPU).absolute)
-	case 0x3D:
-		cpu.and((*CPU).absoluteX)
-
-	case 0x41:
-		cpu.eor((*CPU).indexedIndirect)
-	case 0x51:
-		cpu.eor((*CPU).indirectIndexed)
-	case 0x45:
-		cpu.eor((*CPU).zeroPage)
-	case 0x55:
-		cpu.eor((*CPU).zeroPageX)
-	case 0x49:
-		cpu.eor(nil)
-	case 0x59:
-		cpu.eor((*CPU).absoluteY)
-	case 0x4D:
-		cpu.eor((*CPU).absolute)
-	case 0x5D:
-		cpu.eor((*CPU).absoluteX)
-
-	case 0x61:
-		cpu.adc((*CPU).indexedIndirect)
-	case 0x71:
-		cpu.adc((*CPU).indirectIndexed)
-	case 0x65:
-		cpu.adc((*CPU).zeroPage)
-	case 0x75:
-		cpu.adc((*CPU).zeroPageX)
-	case 0x69:
-		cpu.adc(nil)
-	case 0x79:
-		cpu.adc((*CPU).absoluteY)
-	case 0x6D:
-		cpu.adc((*CPU).absolute)
-	case 0x7D:
-		cpu.adc((*CPU).absoluteX)
-
-	case 0x81:
-		cpu.sta((*CPU).indexedIndirect)
-	case 0x91:
-		cpu.sta((*CPU).indirectIndexed)
-	case 0x85:
-		cpu.sta((*CPU).zeroPage)
-	case 0x95:
-		cpu.sta((*CPU).zeroPageX)
-	case 0x99:
-		cpu.sta((*CPU).absoluteY)
-	case 0x8D:
-		cpu.sta((*CPU).absolute)
-	case 0x9D:
-		cpu.sta((*CPU).absoluteX)
-
-	case 0x84:
-		cpu.sty((*CPU).zeroPage)
-	case 0x94:
-		cpu.sty((*CPU).zeroPageX)
-	case 0x8C:
-		cpu.sty((*CPU).absolute)
-
-	case 0x86:
-		cpu.stx((*CPU).zeroPage)
-	case 0x96:
-		cpu.stx((*CPU).zeroPageY)
-	case 0x8E:
-		cpu.stx((*CPU).absolute)
-
-	case 0xA1:
-		cpu.lda((*CPU).indexedIndirect)
-	case 0xB1:
-		cpu.lda((*CPU).indirectIndexed)
-	case 0xA5:
-		cpu.lda((*CPU).zeroPage)
-	case 0xB5:
-		cpu.lda((*CPU).zeroPageX)
-	case 0xA9:
-		cpu.lda(nil)
-	case 0xB9:
-		cpu.lda((*CPU).absoluteY)
-	case 0xAD:
-		cpu.lda((*CPU).absolute)
-	case 0xBD:
-		cpu.lda((*CPU).absoluteX)
-
-	case 0xA0:
-		cpu.ldy(nil)
-	case 0xA4:
-		cpu.ldy((*CPU).zeroPage)
-	case 0xAC:
-		cpu.ldy((*CPU).absolute)
-	case 0xB4:
-		cpu.ldy((*CPU).zeroPageX)
-	case 0xBC:
-		cpu.ldy((*CPU).absoluteX)
-
-	case 0xA2:
-		cpu.ldx(nil)
-	case 0xA6:
-		cpu.ldx((*CPU).zeroPage)
-	case 0xAE:
-		cpu.ldx((*CPU).absolute)
-	case 0xB6:
-		cpu.ldx((*CPU).zeroPageY)
-	case 0xBE:
-		cpu.ldx((*CPU).absoluteY)
-
-	case 0xC0:
-		cpu.cmp(cpu.y, nil)
-	case 0xC4:
-		cpu.cmp(cpu.y, (*CPU).zeroPage)
-	case 0xCC:
-		cpu.cmp(cpu.y, (*CPU).absolute)
-
-	case 0xE0:
-		cpu.cmp(cpu.x, nil)
-	case 0xE4:
-		cpu.cmp(cpu.x, (*CPU).zeroPage)
-	case 0xEC:
-		cpu.cmp(cpu.x, (*CPU).absolute)
-
-	case 0xC1:
-		cpu.cmp(cpu.acc, (*CPU).indexedIndirect)
-	case 0xD1:
-		cpu.cmp(cpu.acc, (*CPU).indirectIndexed)
-	case 0xC5:
-		cpu.cmp(cpu.acc, (*CPU).zeroPage)
-	case 0xD5:
-		cpu.cmp(cpu.acc, (*CPU).zeroPageX)
-	case 0xC9:
-		cpu.cmp(cpu.acc, nil)
-	case 0xD9:
-		cpu.cmp(cpu.acc, (*CPU).absoluteY)
-	case 0xCD:
-		cpu.cmp(cpu.acc, (*CPU).absolute)
-	case 0xDD:
-		cpu.cmp(cpu.acc, (*CPU).absoluteX)
-
-	case 0xE1:
-		cpu.sbc((*CPU).indexedIndirect)
-	case 0xF1:
-		cpu.sbc((*CPU).indirectIndexed)
-	case 0xE5:
-		cpu.sbc((*CPU).zeroPage)
-	case 0xF5:
-		cpu.sbc((*CPU).zeroPageX)
-	case 0xE9:
-		cpu.sbc(nil)
-	case 0xF9:
-		cpu.sbc((*CPU).absoluteY)
-	case 0xED:
-		cpu.sbc((*CPU).absolute)
-	case 0xFD:
-		cpu.sbc((*CPU).absoluteX)
-
-	case 0x0A:
-		cpu.asl(nil)
-	case 0x06:
-		cpu.asl((*CPU).zeroPage)
-	case 0x16:
-		cpu.asl((*CPU).zeroPageX)
-	case 0x0E:
-		cpu.asl((*CPU).absolute)
-	case 0x1E:
-		cpu.asl((*CPU).absoluteX)
-
-	case 0x2A:
-		cpu.rol(nil)
-	case 0x26:
-		cpu.rol((*CPU).zeroPage)
-	case 0x36:
-		cpu.rol((*CPU).zeroPageX)
-	case 0x2E:
-		cpu.rol((*CPU).absolute)
-	case 0x3E:
-		cpu.rol((*CPU).absoluteX)
-
-	case 0x4A:
-		cpu.lsr(nil)
-	case 0x46:
-		cpu.lsr((*CPU).zeroPage)
-	case 0x56:
-		cpu.lsr((*CPU).zeroPageX)
-	case 0x4E:
-		cpu.lsr((*CPU).absolute)
-	case 0x5E:
-		cpu.lsr((*CPU).absoluteX)
-
-	case 0x6A:
-		cpu.ror(nil)
-	case 0x66:
-		cpu.ror((*CPU).zeroPage)
-	case 0x76:
-		cpu.ror((*CPU).zeroPageX)
-	case 0x6E:
-		cpu.ror((*CPU).absolute)
-	case 0x7E:
-		cpu.ror((*CPU).absoluteX)
-
-	case 0xC6:
-		cpu.dec((*CPU).zeroPage)
-	case 0xD6:
-		cpu.dec((*CPU).zeroPageX)
-	case 0xCE:
-		cpu.dec((*CPU).absolute)
-	case 0xDE:
-		cpu.dec((*CPU).absoluteX)
-
-	case 0xE6:
-		cpu.inc((*CPU).zeroPage)
-	case 0xF6:
-		cpu.inc((*CPU).zeroPageX)
-	case 0xEE:
-		cpu.inc((*CPU).absolute)
-	case 0xFE:
-		cpu.inc((*CPU).absoluteX)
-
-	case 0xCA:
-		cpu.dex()
-	case 0x88:
-		cpu.dey()
-
-	case 0xE8:
-		cpu.inx()
-	case 0xC8:
-		cpu.iny()
-
-	case 0x98: // TYA
+// chargeCrossing adds the page-crossing penalty cycle, for addressing modes
+// where it applies (see addrModeReadFn).
+func (cpu *CPU) chargeCrossing(crossed bool) {
+	if crossed {
+		cpu.cycles++
+	}
+}
+
+// nmosOpcodes is the 256-entry dispatch table for the original NMOS 6502.
+// Adding an opcode means adding an entry here, not editing a switch.
+var nmosOpcodes = buildNMOSOpcodes()
+
+func buildNMOSOpcodes() [256]opcodeFn {
+	var t [256]opcodeFn
+
+	t[0x00] = func(cpu *CPU) { cpu.brk() }
+
+	t[0x01] = func(cpu *CPU) { cpu.ora((*CPU).indexedIndirect) }
+	t[0x11] = func(cpu *CPU) { cpu.ora((*CPU).indirectIndexed) }
+	t[0x05] = func(cpu *CPU) { cpu.ora((*CPU).zeroPage) }
+	t[0x15] = func(cpu *CPU) { cpu.ora((*CPU).zeroPageX) }
+	t[0x09] = func(cpu *CPU) { cpu.ora(nil) }
+	t[0x19] = func(cpu *CPU) { cpu.ora((*CPU).absoluteY) }
+	t[0x0D] = func(cpu *CPU) { cpu.ora((*CPU).absolute) }
+	t[0x1D] = func(cpu *CPU) { cpu.ora((*CPU).absoluteX) }
+
+	t[0x21] = func(cpu *CPU) { cpu.and((*CPU).indexedIndirect) }
+	t[0x31] = func(cpu *CPU) { cpu.and((*CPU).indirectIndexed) }
+	t[0x25] = func(cpu *CPU) { cpu.and((*CPU).zeroPage) }
+	t[0x35] = func(cpu *CPU) { cpu.and((*CPU).zeroPageX) }
+	t[0x29] = func(cpu *CPU) { cpu.and(nil) }
+	t[0x39] = func(cpu *CPU) { cpu.and((*CPU).absoluteY) }
+	t[0x2D] = func(cpu *CPU) { cpu.and((*CPU).absolute) }
+	t[0x3D] = func(cpu *CPU) { cpu.and((*CPU).absoluteX) }
+
+	t[0x41] = func(cpu *CPU) { cpu.eor((*CPU).indexedIndirect) }
+	t[0x51] = func(cpu *CPU) { cpu.eor((*CPU).indirectIndexed) }
+	t[0x45] = func(cpu *CPU) { cpu.eor((*CPU).zeroPage) }
+	t[0x55] = func(cpu *CPU) { cpu.eor((*CPU).zeroPageX) }
+	t[0x49] = func(cpu *CPU) { cpu.eor(nil) }
+	t[0x59] = func(cpu *CPU) { cpu.eor((*CPU).absoluteY) }
+	t[0x4D] = func(cpu *CPU) { cpu.eor((*CPU).absolute) }
+	t[0x5D] = func(cpu *CPU) { cpu.eor((*CPU).absoluteX) }
+
+	t[0x61] = func(cpu *CPU) { cpu.adc((*CPU).indexedIndirect) }
+	t[0x71] = func(cpu *CPU) { cpu.adc((*CPU).indirectIndexed) }
+	t[0x65] = func(cpu *CPU) { cpu.adc((*CPU).zeroPage) }
+	t[0x75] = func(cpu *CPU) { cpu.adc((*CPU).zeroPageX) }
+	t[0x69] = func(cpu *CPU) { cpu.adc(nil) }
+	t[0x79] = func(cpu *CPU) { cpu.adc((*CPU).absoluteY) }
+	t[0x6D] = func(cpu *CPU) { cpu.adc((*CPU).absolute) }
+	t[0x7D] = func(cpu *CPU) { cpu.adc((*CPU).absoluteX) }
+
+	t[0x81] = func(cpu *CPU) { cpu.sta((*CPU).indexedIndirect) }
+	t[0x91] = func(cpu *CPU) { cpu.sta((*CPU).indirectIndexed) }
+	t[0x85] = func(cpu *CPU) { cpu.sta((*CPU).zeroPage) }
+	t[0x95] = func(cpu *CPU) { cpu.sta((*CPU).zeroPageX) }
+	t[0x99] = func(cpu *CPU) { cpu.sta((*CPU).absoluteY) }
+	t[0x8D] = func(cpu *CPU) { cpu.sta((*CPU).absolute) }
+	t[0x9D] = func(cpu *CPU) { cpu.sta((*CPU).absoluteX) }
+
+	t[0x84] = func(cpu *CPU) { cpu.sty((*CPU).zeroPage) }
+	t[0x94] = func(cpu *CPU) { cpu.sty((*CPU).zeroPageX) }
+	t[0x8C] = func(cpu *CPU) { cpu.sty((*CPU).absolute) }
+
+	t[0x86] = func(cpu *CPU) { cpu.stx((*CPU).zeroPage) }
+	t[0x96] = func(cpu *CPU) { cpu.stx((*CPU).zeroPageY) }
+	t[0x8E] = func(cpu *CPU) { cpu.stx((*CPU).absolute) }
+
+	t[0xA1] = func(cpu *CPU) { cpu.lda((*CPU).indexedIndirect) }
+	t[0xB1] = func(cpu *CPU) { cpu.lda((*CPU).indirectIndexed) }
+	t[0xA5] = func(cpu *CPU) { cpu.lda((*CPU).zeroPage) }
+	t[0xB5] = func(cpu *CPU) { cpu.lda((*CPU).zeroPageX) }
+	t[0xA9] = func(cpu *CPU) { cpu.lda(nil) }
+	t[0xB9] = func(cpu *CPU) { cpu.lda((*CPU).absoluteY) }
+	t[0xAD] = func(cpu *CPU) { cpu.lda((*CPU).absolute) }
+	t[0xBD] = func(cpu *CPU) { cpu.lda((*CPU).absoluteX) }
+
+	t[0xA0] = func(cpu *CPU) { cpu.ldy(nil) }
+	t[0xA4] = func(cpu *CPU) { cpu.ldy((*CPU).zeroPage) }
+	t[0xAC] = func(cpu *CPU) { cpu.ldy((*CPU).absolute) }
+	t[0xB4] = func(cpu *CPU) { cpu.ldy((*CPU).zeroPageX) }
+	t[0xBC] = func(cpu *CPU) { cpu.ldy((*CPU).absoluteX) }
+
+	t[0xA2] = func(cpu *CPU) { cpu.ldx(nil) }
+	t[0xA6] = func(cpu *CPU) { cpu.ldx((*CPU).zeroPage) }
+	t[0xAE] = func(cpu *CPU) { cpu.ldx((*CPU).absolute) }
+	t[0xB6] = func(cpu *CPU) { cpu.ldx((*CPU).zeroPageY) }
+	t[0xBE] = func(cpu *CPU) { cpu.ldx((*CPU).absoluteY) }
+
+	t[0xC0] = func(cpu *CPU) { cpu.cmp(cpu.y, nil) }
+	t[0xC4] = func(cpu *CPU) { cpu.cmp(cpu.y, (*CPU).zeroPage) }
+	t[0xCC] = func(cpu *CPU) { cpu.cmp(cpu.y, (*CPU).absolute) }
+
+	t[0xE0] = func(cpu *CPU) { cpu.cmp(cpu.x, nil) }
+	t[0xE4] = func(cpu *CPU) { cpu.cmp(cpu.x, (*CPU).zeroPage) }
+	t[0xEC] = func(cpu *CPU) { cpu.cmp(cpu.x, (*CPU).absolute) }
+
+	t[0xC1] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).indexedIndirect) }
+	t[0xD1] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).indirectIndexed) }
+	t[0xC5] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).zeroPage) }
+	t[0xD5] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).zeroPageX) }
+	t[0xC9] = func(cpu *CPU) { cpu.cmp(cpu.acc, nil) }
+	t[0xD9] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).absoluteY) }
+	t[0xCD] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).absolute) }
+	t[0xDD] = func(cpu *CPU) { cpu.cmp(cpu.acc, (*CPU).absoluteX) }
+
+	t[0xE1] = func(cpu *CPU) { cpu.sbc((*CPU).indexedIndirect) }
+	t[0xF1] = func(cpu *CPU) { cpu.sbc((*CPU).indirectIndexed) }
+	t[0xE5] = func(cpu *CPU) { cpu.sbc((*CPU).zeroPage) }
+	t[0xF5] = func(cpu *CPU) { cpu.sbc((*CPU).zeroPageX) }
+	t[0xE9] = func(cpu *CPU) { cpu.sbc(nil) }
+	t[0xF9] = func(cpu *CPU) { cpu.sbc((*CPU).absoluteY) }
+	t[0xED] = func(cpu *CPU) { cpu.sbc((*CPU).absolute) }
+	t[0xFD] = func(cpu *CPU) { cpu.sbc((*CPU).absoluteX) }
+
+	t[0x0A] = func(cpu *CPU) { cpu.asl(nil) }
+	t[0x06] = func(cpu *CPU) { cpu.asl((*CPU).zeroPage) }
+	t[0x16] = func(cpu *CPU) { cpu.asl((*CPU).zeroPageX) }
+	t[0x0E] = func(cpu *CPU) { cpu.asl((*CPU).absolute) }
+	t[0x1E] = func(cpu *CPU) { cpu.asl((*CPU).absoluteX) }
+
+	t[0x2A] = func(cpu *CPU) { cpu.rol(nil) }
+	t[0x26] = func(cpu *CPU) { cpu.rol((*CPU).zeroPage) }
+	t[0x36] = func(cpu *CPU) { cpu.rol((*CPU).zeroPageX) }
+	t[0x2E] = func(cpu *CPU) { cpu.rol((*CPU).absolute) }
+	t[0x3E] = func(cpu *CPU) { cpu.rol((*CPU).absoluteX) }
+
+	t[0x4A] = func(cpu *CPU) { cpu.lsr(nil) }
+	t[0x46] = func(cpu *CPU) { cpu.lsr((*CPU).zeroPage) }
+	t[0x56] = func(cpu *CPU) { cpu.lsr((*CPU).zeroPageX) }
+	t[0x4E] = func(cpu *CPU) { cpu.lsr((*CPU).absolute) }
+	t[0x5E] = func(cpu *CPU) { cpu.lsr((*CPU).absoluteX) }
+
+	t[0x6A] = func(cpu *CPU) { cpu.ror(nil) }
+	t[0x66] = func(cpu *CPU) { cpu.ror((*CPU).zeroPage) }
+	t[0x76] = func(cpu *CPU) { cpu.ror((*CPU).zeroPageX) }
+	t[0x6E] = func(cpu *CPU) { cpu.ror((*CPU).absolute) }
+	t[0x7E] = func(cpu *CPU) { cpu.ror((*CPU).absoluteX) }
+
+	t[0xC6] = func(cpu *CPU) { cpu.dec((*CPU).zeroPage) }
+	t[0xD6] = func(cpu *CPU) { cpu.dec((*CPU).zeroPageX) }
+	t[0xCE] = func(cpu *CPU) { cpu.dec((*CPU).absolute) }
+	t[0xDE] = func(cpu *CPU) { cpu.dec((*CPU).absoluteX) }
+
+	t[0xE6] = func(cpu *CPU) { cpu.inc((*CPU).zeroPage) }
+	t[0xF6] = func(cpu *CPU) { cpu.inc((*CPU).zeroPageX) }
+	t[0xEE] = func(cpu *CPU) { cpu.inc((*CPU).absolute) }
+	t[0xFE] = func(cpu *CPU) { cpu.inc((*CPU).absoluteX) }
+
+	t[0xCA] = func(cpu *CPU) { cpu.dex() }
+	t[0x88] = func(cpu *CPU) { cpu.dey() }
+
+	t[0xE8] = func(cpu *CPU) { cpu.inx() }
+	t[0xC8] = func(cpu *CPU) { cpu.iny() }
+
+	t[0x98] = func(cpu *CPU) { // TYA
 		cpu.setNZ(cpu.y)
 		cpu.acc = cpu.y
-	case 0xA8: // TAY
+	}
+	t[0xA8] = func(cpu *CPU) { // TAY
 		cpu.setNZ(cpu.acc)
 		cpu.y = cpu.acc
-	case 0x8A: // TXA
+	}
+	t[0x8A] = func(cpu *CPU) { // TXA
 		cpu.setNZ(cpu.x)
 		cpu.acc = cpu.x
-	case 0x9A: // TXS
+	}
+	t[0x9A] = func(cpu *CPU) { // TXS
 		cpu.setNZ(cpu.x)
 		cpu.sp = cpu.x
-	case 0xAA: // TAX
+	}
+	t[0xAA] = func(cpu *CPU) { // TAX
 		cpu.setNZ(cpu.acc)
 		cpu.x = cpu.acc
-	case 0xBA: // TSX
+	}
+	t[0xBA] = func(cpu *CPU) { // TSX
 		cpu.setNZ(cpu.sp)
 		cpu.x = cpu.sp
+	}
+
+	t[0x18] = func(cpu *CPU) { cpu.pf.Clear(C) } // CLC
+	t[0x38] = func(cpu *CPU) { cpu.pf.Set(C) } // SEC
+	t[0x58] = func(cpu *CPU) { cpu.pf.Clear(I) } // CLI
+	t[0x78] = func(cpu *CPU) { cpu.pf.Set(I) } // SEI
+	t[0xB8] = func(cpu *CPU) { cpu.pf.Clear(V) } // CLV
+	t[0xD8] = func(cpu *CPU) { cpu.pf.Clear(D) } // CLD
+	t[0xF8] = func(cpu *CPU) { cpu.pf.Set(D) } // SED
+
+	t[0x24] = func(cpu *CPU) { cpu.bit((*CPU).zeroPage) }
+	t[0x2C] = func(cpu *CPU) { cpu.bit((*CPU).absolute) }
+
+	t[0x08] = func(cpu *CPU) { cpu.stackPush(uint8(cpu.pf)) } // PHP
+	t[0x28] = func(cpu *CPU) { cpu.pf = ProgramFlags(cpu.stackPop()) } // PLP
+	t[0x48] = func(cpu *CPU) { cpu.stackPush(cpu.acc) } // PHA
+	t[0x68] = func(cpu *CPU) { cpu.acc = cpu.stackPop() } // PLA
+
+	t[0x10] = func(cpu *CPU) { cpu.branch(!cpu.pf.Test(N)) } // BPL
+	t[0x30] = func(cpu *CPU) { cpu.branch(cpu.pf.Test(N)) } // BMI
+	t[0x50] = func(cpu *CPU) { cpu.branch(!cpu.pf.Test(V)) } // BVC
+	t[0x70] = func(cpu *CPU) { cpu.branch(cpu.pf.Test(V)) } // BVS
+	t[0x90] = func(cpu *CPU) { cpu.branch(!cpu.pf.Test(C)) } // BCC
+	t[0xB0] = func(cpu *CPU) { cpu.branch(cpu.pf.Test(C)) } // BCS
+	t[0xD0] = func(cpu *CPU) { cpu.branch(!cpu.pf.Test(Z)) } // BNE
+	t[0xF0] = func(cpu *CPU) { cpu.branch(cpu.pf.Test(Z)) } // BEQ
+
+	t[0x20] = func(cpu *CPU) { cpu.jsr() }
+	t[0x4C] = func(cpu *CPU) { cpu.jmpAbsolute() }
+	t[0x6C] = func(cpu *CPU) { cpu.jmpIndirect() }
+	t[0x40] = func(cpu *CPU) { cpu.rti() }
+	t[0x60] = func(cpu *CPU) { cpu.rts() }
+
+	t[0xEA] = func(cpu *CPU) {} // NOP
+
+	addIllegalOpcodes(&t)
+
+	return t
+}
+
+// nmosCycles holds the base cycle count for each NMOS opcode: the fixed cost
+// of the addressing mode and instruction, before branch() or chargeCrossing()
+// add any penalty for a taken branch or a crossed page boundary. Once
+// addIllegalCycles has run, every one of the 256 slots has a cost, matching
+// nmosOpcodes, which has no nil entry.
+var nmosCycles = buildNMOSCycles()
+
+func buildNMOSCycles() [256]uint8 {
+	var t [256]uint8
+
+	// ORA, AND, EOR, ADC, LDA, CMP, SBC all share the same addressing-mode
+	// shape: (ind,X) 6, zp 3, imm 2, abs 4, (ind),Y 5, zpX 4, absY 4, absX 4.
+	for _, col := range [...]uint8{0x01, 0x21, 0x41, 0x61, 0xA1, 0xC1, 0xE1} {
+		t[col] = 6
+	}
+	for _, col := range [...]uint8{0x05, 0x25, 0x45, 0x65, 0xA5, 0xC5, 0xE5} {
+		t[col] = 3
+	}
+	for _, col := range [...]uint8{0x09, 0x29, 0x49, 0x69, 0xA9, 0xC9, 0xE9} {
+		t[col] = 2
+	}
+	for _, col := range [...]uint8{0x0D, 0x2D, 0x4D, 0x6D, 0xAD, 0xCD, 0xED} {
+		t[col] = 4
+	}
+	for _, col := range [...]uint8{0x11, 0x31, 0x51, 0x71, 0xB1, 0xD1, 0xF1} {
+		t[col] = 5
+	}
+	for _, col := range [...]uint8{0x15, 0x35, 0x55, 0x75, 0xB5, 0xD5, 0xF5} {
+		t[col] = 4
+	}
+	for _, col := range [...]uint8{0x19, 0x39, 0x59, 0x79, 0xB9, 0xD9, 0xF9} {
+		t[col] = 4
+	}
+	for _, col := range [...]uint8{0x1D, 0x3D, 0x5D, 0x7D, 0xBD, 0xDD, 0xFD} {
+		t[col] = 4
+	}
+
+	// STA.
+	t[0x81] = 6
+	t[0x85] = 3
+	t[0x8D] = 4
+	t[0x91] = 6
+	t[0x95] = 4
+	t[0x99] = 5
+	t[0x9D] = 5
+
+	// STY / STX.
+	t[0x84] = 3
+	t[0x94] = 4
+	t[0x8C] = 4
+	t[0x86] = 3
+	t[0x96] = 4
+	t[0x8E] = 4
+
+	// LDY / LDX.
+	t[0xA0] = 2
+	t[0xA4] = 3
+	t[0xAC] = 4
+	t[0xB4] = 4
+	t[0xBC] = 4
+	t[0xA2] = 2
+	t[0xA6] = 3
+	t[0xAE] = 4
+	t[0xB6] = 4
+	t[0xBE] = 4
+
+	// CPY / CPX.
+	t[0xC0] = 2
+	t[0xC4] = 3
+	t[0xCC] = 4
+	t[0xE0] = 2
+	t[0xE4] = 3
+	t[0xEC] = 4
+
+	// ASL, ROL, LSR, ROR: accumulator 2, zp 5, zpX 6, abs 6, absX 7.
+	for _, col := range [...]uint8{0x0A, 0x2A, 0x4A, 0x6A} {
+		t[col] = 2
+	}
+	for _, col := range [...]uint8{0x06, 0x26, 0x46, 0x66} {
+		t[col] = 5
+	}
+	for _, col := range [...]uint8{0x16, 0x36, 0x56, 0x76} {
+		t[col] = 6
+	}
+	for _, col := range [...]uint8{0x0E, 0x2E, 0x4E, 0x6E} {
+		t[col] = 6
+	}
+	for _, col := range [...]uint8{0x1E, 0x3E, 0x5E, 0x7E} {
+		t[col] = 7
+	}
+
+	// DEC / INC.
+	t[0xC6] = 5
+	t[0xD6] = 6
+	t[0xCE] = 6
+	t[0xDE] = 7
+	t[0xE6] = 5
+	t[0xF6] = 6
+	t[0xEE] = 6
+	t[0xFE] = 7
+
+	// Single-byte register and flag instructions.
+	for _, col := range [...]uint8{
+		0xCA, 0x88, 0xE8, 0xC8, // DEX, DEY, INX, INY
+		0x98, 0xA8, 0x8A, 0x9A, 0xAA, 0xBA, // TYA, TAY, TXA, TXS, TAX, TSX
+		0x18, 0x38, 0x58, 0x78, 0xB8, 0xD8, 0xF8, // CLC, SEC, CLI, SEI, CLV, CLD, SED
+		0xEA, // NOP
+	} {
+		t[col] = 2
+	}
+
+	// BIT.
+	t[0x24] = 3
+	t[0x2C] = 4
+
+	// Stack instructions.
+	t[0x08] = 3 // PHP
+	t[0x28] = 4 // PLP
+	t[0x48] = 3 // PHA
+	t[0x68] = 4 // PLA
 
-	case 0x18:
-		cpu.pf.Clear(C) // CLC
-	case 0x38:
-		cpu.pf.Set(C) // SEC
-	case 0x58:
-		cpu.pf.Clear(I) // CLI
-	case 0x78:
-		cpu.pf.Set(I) // SEI
-	case 0xB8:
-		cpu.pf.Clear(V) // CLV
-	case 0xD8:
-		cpu.pf.Clear(D) // CLD
-	case 0xF8:
-		cpu.pf.Set(D) //SED
-
-	case 0x24:
-		cpu.bit((*CPU).zeroPage)
-	case 0x2C:
-		cpu.bit((*CPU).absolute)
-
-	case 0x08:
-		cpu.stackPush(uint8(cpu.pf)) // PHP
-	case 0x28:
-		cpu.pf = ProgramFlags(cpu.stackPop()) // PLP
-	case 0x48:
-		cpu.stackPush(cpu.acc) // PHA
-	case 0x68:
-		cpu.acc = cpu.stackPop() // PLA
-
-	case 0x10:
-		cpu.branch(!cpu.pf.Test(N)) // BPL
-	case 0x30:
-		cpu.branch(cpu.pf.Test(N)) // BMI
-	case 0x50:
-		cpu.branch(!cpu.pf.Test(V)) // BVC
-	case 0x70:
-		cpu.branch(cpu.pf.Test(V)) // BVS
-	case 0x90:
-		cpu.branch(!cpu.pf.Test(C)) // BCC
-	case 0xB0:
-		cpu.branch(cpu.pf.Test(C)) // BCS
-	case 0xD0:
-		cpu.branch(!cpu.pf.Test(Z)) // BNE
-	case 0xF0:
-		cpu.branch(cpu.pf.Test(Z)) // BEQ
-
-	case 0x20:
-		cpu.jsr()
-	case 0x4C:
-		cpu.jmpAbsolute()
-	case 0x6C:
-		cpu.jmpIndirect()
-	case 0x40:
-		cpu.rti()
-	case 0x60:
-		cpu.rts()
+	// Branches: base cost before the taken/page-crossed penalties branch()
+	// adds itself.
+	for _, col := range [...]uint8{0x10, 0x30, 0x50, 0x70, 0x90, 0xB0, 0xD0, 0xF0} {
+		t[col] = 2
 	}
+
+	// Jumps, subroutines, interrupts.
+	t[0x20] = 6 // JSR
+	t[0x4C] = 3 // JMP abs
+	t[0x6C] = 5 // JMP (ind)
+	t[0x40] = 6 // RTI
+	t[0x60] = 6 // RTS
+	t[0x00] = 7 // BRK
+
+	addIllegalCycles(&t)
+
+	return t
 }
 
 /*** Basic Memory ***/
 
 // Read from the bus.
 func (cpu *CPU) memRead(addr uint16) uint8 {
-	cpu.cycles++
+	if cpu.watchpoints != nil {
+		cpu.checkWatch(addr, WatchRead)
+	}
 	return cpu.memBus.Read(addr)
 }
 
 // Write to the bus.
 func (cpu *CPU) memWrite(addr uint16, data uint8) {
-	cpu.cycles++
+	if cpu.watchpoints != nil {
+		cpu.checkWatch(addr, WatchWrite)
+	}
 	cpu.memBus.Write(addr, data)
 }
 
@@ -461,62 +648,71 @@ func (cpu *CPU) stackPop() uint8 {
 /*** Addressing modes ***/
 
 // $xx
-func (cpu *CPU) zeroPage() uint16 {
-	return uint16(cpu.fetch())
+func (cpu *CPU) zeroPage() (uint16, bool) {
+	return uint16(cpu.fetch()), false
 }
 
 // $xx, X
-func (cpu *CPU) zeroPageX() uint16 {
-	return uint16(cpu.fetch() + cpu.x)
+func (cpu *CPU) zeroPageX() (uint16, bool) {
+	return uint16(cpu.fetch() + cpu.x), false
 }
 
 // $xx, Y
-func (cpu *CPU) zeroPageY() uint16 {
-	return uint16(cpu.fetch() + cpu.y)
+func (cpu *CPU) zeroPageY() (uint16, bool) {
+	return uint16(cpu.fetch() + cpu.y), false
 }
 
 // $xxxx
-func (cpu *CPU) absolute() uint16 {
+func (cpu *CPU) absolute() (uint16, bool) {
 	var addrLo = cpu.fetch()
 	var addrHi = cpu.fetch()
 
-	return Make16(addrHi, addrLo)
+	return Make16(addrHi, addrLo), false
 }
 
 // $xxxx, X
-func (cpu *CPU) absoluteX() uint16 {
+func (cpu *CPU) absoluteX() (uint16, bool) {
 	var addrLo = cpu.fetch()
 	var addrHi = cpu.fetch()
 
-	return Make16(addrHi, addrLo) + uint16(cpu.x)
+	var baseAddr = Make16(addrHi, addrLo)
+	var addr = baseAddr + uint16(cpu.x)
+
+	return addr, Hi(baseAddr) != Hi(addr)
 }
 
 // $xxxx, Y
-func (cpu *CPU) absoluteY() uint16 {
+func (cpu *CPU) absoluteY() (uint16, bool) {
 	var addrLo = cpu.fetch()
 	var addrHi = cpu.fetch()
 
-	return Make16(addrHi, addrLo) + uint16(cpu.y)
+	var baseAddr = Make16(addrHi, addrLo)
+	var addr = baseAddr + uint16(cpu.y)
+
+	return addr, Hi(baseAddr) != Hi(addr)
 }
 
 // ($xx, X)
-func (cpu *CPU) indexedIndirect() uint16 {
+func (cpu *CPU) indexedIndirect() (uint16, bool) {
 	var target = uint16(cpu.fetch() + cpu.x)
 
 	var addrLo = cpu.memRead(target)
 	var addrHi = cpu.memRead(target + 1)
 
-	return Make16(addrHi, addrLo)
+	return Make16(addrHi, addrLo), false
 }
 
 // ($xx), Y
-func (cpu *CPU) indirectIndexed() uint16 {
+func (cpu *CPU) indirectIndexed() (uint16, bool) {
 	var target = uint16(cpu.fetch())
 
 	var addrLo = cpu.memRead(target)
 	var addrHi = cpu.memRead(target + 1)
 
-	return Make16(addrHi, addrLo) + uint16(cpu.y)
+	var baseAddr = Make16(addrHi, addrLo)
+	var addr = baseAddr + uint16(cpu.y)
+
+	return addr, Hi(baseAddr) != Hi(addr)
 }
 
 // Addressing modes
@@ -526,35 +722,51 @@ func (cpu *CPU) indirectIndexed() uint16 {
 /*** Arithmetic ***/
 
 func (cpu *CPU) adc(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 
 	if cpu.pf.Test(D) {
-		// Decimal
+		cpu.decimalAdd(data)
 	} else {
 		cpu.binaryArithmetic(data)
 	}
 }
 
 func (cpu *CPU) sbc(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 
 	if cpu.pf.Test(D) {
-		// Decimal
+		cpu.decimalSub(data)
 	} else {
 		cpu.binaryArithmetic(^data)
 	}
 }
 
+// inc increments memory, or (CMOS-only, addrMode nil) the accumulator.
 func (cpu *CPU) inc(addrMode addrModeReadFn) {
-	data, addr := cpu.dataAddr(addrMode)
+	if addrMode == nil {
+		cpu.acc++
+		cpu.setNZ(cpu.acc)
+		return
+	}
 
-	cpu.memWrite(addr, data+1)
+	data, addr, _ := cpu.dataAddr(addrMode)
+	cpu.rmwWrite(addr, data, data+1)
+	cpu.setNZ(data + 1)
 }
 
+// dec decrements memory, or (CMOS-only, addrMode nil) the accumulator.
 func (cpu *CPU) dec(addrMode addrModeReadFn) {
-	data, addr := cpu.dataAddr(addrMode)
+	if addrMode == nil {
+		cpu.acc--
+		cpu.setNZ(cpu.acc)
+		return
+	}
 
-	cpu.memWrite(addr, data-1)
+	data, addr, _ := cpu.dataAddr(addrMode)
+	cpu.rmwWrite(addr, data, data-1)
+	cpu.setNZ(data - 1)
 }
 
 func (cpu *CPU) inx() {
@@ -576,19 +788,22 @@ func (cpu *CPU) dey() {
 /*** Bitwise ***/
 
 func (cpu *CPU) ora(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.acc |= data
 	cpu.setNZ(cpu.acc)
 }
 
 func (cpu *CPU) and(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.acc &= data
 	cpu.setNZ(cpu.acc)
 }
 
 func (cpu *CPU) eor(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.acc ^= data
 	cpu.setNZ(cpu.acc)
 }
@@ -599,18 +814,20 @@ func (cpu *CPU) asl(addrMode addrModeReadFn) {
 	var data uint8
 	var addr uint16
 	if addrMode == nil {
-		data, addr = cpu.dataAddr(addrMode)
-	} else {
 		data = cpu.acc
+	} else {
+		data, addr, _ = cpu.dataAddr(addrMode)
 	}
 
+	var result = data << 1
+
 	cpu.pf.SetIf(C, (data&highBit) != 0)
-	cpu.setNZ(data)
+	cpu.setNZ(result)
 
 	if addrMode == nil {
-		cpu.memWrite(addr, data<<1)
+		cpu.acc = result
 	} else {
-		cpu.acc = data << 1
+		cpu.rmwWrite(addr, data, result)
 	}
 }
 
@@ -620,18 +837,20 @@ func (cpu *CPU) lsr(addrMode addrModeReadFn) {
 	var data uint8
 	var addr uint16
 	if addrMode == nil {
-		data, addr = cpu.dataAddr(addrMode)
-	} else {
 		data = cpu.acc
+	} else {
+		data, addr, _ = cpu.dataAddr(addrMode)
 	}
 
+	var result = data >> 1
+
 	cpu.pf.SetIf(C, (data&lowBit) != 0)
-	cpu.setNZ(data)
+	cpu.setNZ(result)
 
 	if addrMode == nil {
-		cpu.memWrite(addr, data>>1)
+		cpu.acc = result
 	} else {
-		cpu.acc = data >> 1
+		cpu.rmwWrite(addr, data, result)
 	}
 }
 
@@ -641,21 +860,21 @@ func (cpu *CPU) rol(addrMode addrModeReadFn) {
 	var data uint8
 	var addr uint16
 	if addrMode == nil {
-		data, addr = cpu.dataAddr(addrMode)
-	} else {
 		data = cpu.acc
+	} else {
+		data, addr, _ = cpu.dataAddr(addrMode)
 	}
 
 	var carry = uint8(cpu.pf & C)
 	var result = (data << 1) | carry
 
 	cpu.pf.SetIf(C, (data&highBit) != 0)
-	cpu.setNZ(data)
+	cpu.setNZ(result)
 
 	if addrMode == nil {
-		cpu.memWrite(addr, result)
-	} else {
 		cpu.acc = result
+	} else {
+		cpu.rmwWrite(addr, data, result)
 	}
 }
 
@@ -665,55 +884,58 @@ func (cpu *CPU) ror(addrMode addrModeReadFn) {
 	var data uint8
 	var addr uint16
 	if addrMode == nil {
-		data, addr = cpu.dataAddr(addrMode)
-	} else {
 		data = cpu.acc
+	} else {
+		data, addr, _ = cpu.dataAddr(addrMode)
 	}
 
 	var carry = uint8(cpu.pf&C) << 7
 	var result = (data >> 1) | carry
 
 	cpu.pf.SetIf(C, (data&lowBit) != 0)
-	cpu.setNZ(data)
+	cpu.setNZ(result)
 
 	if addrMode == nil {
-		cpu.memWrite(addr, result)
-	} else {
 		cpu.acc = result
+	} else {
+		cpu.rmwWrite(addr, data, result)
 	}
 }
 
 /*** Data moving ***/
 
 func (cpu *CPU) sta(addrMode addrModeReadFn) {
-	addr := addrMode(cpu)
+	addr, _ := addrMode(cpu)
 	cpu.memWrite(addr, cpu.acc)
 }
 
 func (cpu *CPU) sty(addrMode addrModeReadFn) {
-	addr := addrMode(cpu)
+	addr, _ := addrMode(cpu)
 	cpu.memWrite(addr, cpu.y)
 }
 
 func (cpu *CPU) stx(addrMode addrModeReadFn) {
-	addr := addrMode(cpu)
+	addr, _ := addrMode(cpu)
 	cpu.memWrite(addr, cpu.x)
 }
 
 func (cpu *CPU) lda(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.setNZ(data)
 	cpu.acc = data
 }
 
 func (cpu *CPU) ldy(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.setNZ(data)
 	cpu.y = data
 }
 
 func (cpu *CPU) ldx(addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 	cpu.setNZ(data)
 	cpu.x = data
 }
@@ -721,7 +943,8 @@ func (cpu *CPU) ldx(addrMode addrModeReadFn) {
 /*** Flags ***/
 
 func (cpu *CPU) cmp(reg uint8, addrMode addrModeReadFn) {
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 
 	cpu.setNZ(reg - data)
 	cpu.pf.SetIf(C, reg >= data)
@@ -731,7 +954,8 @@ func (cpu *CPU) bit(addrMode addrModeReadFn) {
 	const signBit = 1 << 7
 	const overflowBit = 1 << 6
 
-	data, _ := cpu.dataAddr(addrMode)
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
 
 	cpu.pf.SetIf(N, (data&signBit) != 0)
 	cpu.pf.SetIf(V, (data&overflowBit) != 0)
@@ -740,20 +964,29 @@ func (cpu *CPU) bit(addrMode addrModeReadFn) {
 
 /*** Branches ***/
 
+// branch adds the taken (+1 cycle) and page-crossed (+1 more) penalties on
+// top of the base cycle count, which only covers the opcode and offset byte
+// fetch.
 func (cpu *CPU) branch(cond bool) {
 	data := int16(int8(cpu.fetch()))
 
 	if cond {
+		var oldPC = cpu.pc
 		cpu.pc += uint16(data)
+		cpu.cycles++
+		if Hi(oldPC) != Hi(cpu.pc) {
+			cpu.cycles++
+		}
 	}
 }
 
 func (cpu *CPU) jmpAbsolute() {
-	cpu.pc = cpu.absolute()
+	var addr, _ = cpu.absolute()
+	cpu.pc = addr
 }
 
 func (cpu *CPU) jmpIndirect() {
-	var addr = cpu.absolute()
+	var addr, _ = cpu.absolute()
 
 	var pcLo = cpu.memRead(addr)
 	var pcHi = cpu.memRead(Make16(Hi(addr), Lo(addr)+1))
@@ -762,7 +995,7 @@ func (cpu *CPU) jmpIndirect() {
 }
 
 func (cpu *CPU) jsr() {
-	var addr = cpu.absolute()
+	var addr, _ = cpu.absolute()
 
 	var storePC = cpu.pc - 1
 	cpu.stackPush(Hi(storePC))
@@ -789,14 +1022,28 @@ func (cpu *CPU) rti() {
 
 /*** MISC ***/
 
+// brk pushes PC+1, not the already-fetched PC: the byte after the BRK
+// opcode is a padding/signature byte that real hardware skips over but
+// still advances PC past, so RTI resumes two bytes past the opcode. The B
+// flag is set only in the pushed copy of the flags, never in cpu.pf
+// itself, since B isn't a real status register bit.
 func (cpu *CPU) brk() {
-	cpu.pf.Set(B)
-	cpu.interruptRoutine(IRQ, 0xFFFE)
-	cpu.pf.Clear(B)
+	cpu.pc++
+	cpu.interruptRoutine(IRQ, 0xFFFE, cpu.pf|B)
 }
 
 /*** Instruction Helpers ***/
 
+// rmwWrite writes back the result of a read-modify-write instruction. NMOS
+// hardware writes the unmodified value back to the bus before writing the
+// final result; CMOS (65C02) does a single read and a single write.
+func (cpu *CPU) rmwWrite(addr uint16, old, result uint8) {
+	if !cpu.cmos {
+		cpu.memWrite(addr, old)
+	}
+	cpu.memWrite(addr, result)
+}
+
 func (cpu *CPU) setNZ(data uint8) {
 	const signBit = 1 << 7
 	cpu.pf.SetIf(N, (data&signBit) != 0)
@@ -812,20 +1059,95 @@ func (cpu *CPU) binaryArithmetic(data uint8) {
 	var finalResult = Lo(result)
 
 	cpu.pf.SetIf(N, (finalResult&signBit) != 0)
-	cpu.pf.SetIf(V, ^((cpu.acc^data)&(cpu.acc^finalResult)) == signBit)
+	cpu.pf.SetIf(V, (^(cpu.acc^data)&(cpu.acc^finalResult)&signBit) != 0)
 	cpu.pf.SetIf(Z, finalResult == 0)
 	cpu.pf.SetIf(C, (result&carryBit) != 0)
 
 	cpu.acc = finalResult
 }
 
-// Resolve an address and load the data.
-func (cpu *CPU) dataAddr(addrMode addrModeReadFn) (data uint8, addr uint16) {
+// decimalAdd implements BCD ADC, working a nibble at a time as real hardware
+// does. On NMOS, N and Z reflect the binary (pre-decimal-adjust) sum rather
+// than the BCD result, a well known hardware quirk that Klaus Dormann's
+// decimal-mode test depends on; CMOS computes N and Z from the final BCD
+// result instead, and costs one extra cycle.
+func (cpu *CPU) decimalAdd(data uint8) {
+	const signBit = 1 << 7
+
+	var a = cpu.acc
+	var carry = int(cpu.pf & C)
+
+	var binResult = a + data + uint8(carry)
+
+	var al = int(a&0x0F) + int(data&0x0F) + carry
+	if al > 9 {
+		al = ((al + 6) & 0x0F) + 0x10
+	}
+
+	var sum = int(a&0xF0) + int(data&0xF0) + al
+
+	// V is set from the pre-correction high-nibble sum, before the $60 fixup.
+	cpu.pf.SetIf(V, ((a^uint8(sum))&(data^uint8(sum))&signBit) != 0)
+
+	if sum >= 0xA0 {
+		sum += 0x60
+	}
+	cpu.pf.SetIf(C, sum >= 0x100)
+
+	cpu.acc = uint8(sum)
+
+	if cpu.cmos {
+		cpu.setNZ(cpu.acc)
+		cpu.cycles++
+	} else {
+		cpu.setNZ(binResult)
+	}
+}
+
+// decimalSub implements BCD SBC: the mirror of decimalAdd, subtracting a
+// nibble at a time with borrow and taking 6 off a nibble that goes below
+// zero. See decimalAdd for the NMOS/CMOS N,Z quirk.
+func (cpu *CPU) decimalSub(data uint8) {
+	const signBit = 1 << 7
+
+	var a = cpu.acc
+	var carry = int(cpu.pf & C)
+
+	var binResult = a + ^data + uint8(carry)
+
+	var al = int(a&0x0F) - int(data&0x0F) + carry - 1
+	if al < 0 {
+		al = ((al - 6) & 0x0F) - 0x10
+	}
+
+	var sum = int(a&0xF0) - int(data&0xF0) + al
+
+	var compl = ^data
+	cpu.pf.SetIf(V, ((a^uint8(sum))&(compl^uint8(sum))&signBit) != 0)
+
+	if sum < 0 {
+		sum -= 0x60
+	}
+	cpu.pf.SetIf(C, sum >= 0)
+
+	cpu.acc = uint8(sum)
+
+	if cpu.cmos {
+		cpu.setNZ(cpu.acc)
+		cpu.cycles++
+	} else {
+		cpu.setNZ(binResult)
+	}
+}
+
+// Resolve an address and load the data, reporting whether computing the
+// address crossed a page boundary.
+func (cpu *CPU) dataAddr(addrMode addrModeReadFn) (data uint8, addr uint16, crossed bool) {
 	if addrMode == nil {
 		addr = 0
 		data = cpu.fetch()
 	} else {
-		addr = addrMode(cpu)
+		addr, crossed = addrMode(cpu)
 		data = cpu.memRead(addr)
 	}
 
@@ -0,0 +1,134 @@
+package go6502
+
+import (
+	"testing"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// TestReset checks the power-on/reset sequence: SP set to $FD, I set, D
+// cleared, PC loaded from the reset vector, and 7 cycles charged.
+func TestReset(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0xFFFC] = 0x34
+	mem.mem[0xFFFD] = 0x12
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.sp = 0x00
+	cpu.pf.Set(D)
+
+	cpu.Reset()
+
+	if cpu.sp != 0xFD {
+		t.Errorf("sp = $%02X, want $FD", cpu.sp)
+	}
+	if !cpu.pf.Test(I) {
+		t.Error("I flag not set after Reset")
+	}
+	if cpu.pf.Test(D) {
+		t.Error("D flag still set after Reset")
+	}
+	if cpu.pc != 0x1234 {
+		t.Errorf("pc = $%04X, want $1234 (from reset vector)", cpu.pc)
+	}
+	if cpu.totalCycles != 7 {
+		t.Errorf("totalCycles = %d, want 7", cpu.totalCycles)
+	}
+}
+
+// TestTriggerIRQMaskedByIFlag checks that TriggerIRQ only takes effect once
+// the I flag is clear, unlike TriggerNMI.
+func TestTriggerIRQMaskedByIFlag(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0xFFFE] = 0x00
+	mem.mem[0xFFFF] = 0x20
+	mem.mem[0x0000] = 0xEA // NOP
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.pf.Set(I)
+	cpu.TriggerIRQ()
+
+	cpu.Step()
+	if cpu.pc != 0x0000 {
+		t.Fatalf("pc = $%04X after Step with I set, want $0000 (IRQ should stay pending, masked)", cpu.pc)
+	}
+
+	cpu.pf.Clear(I)
+	cpu.Step()
+	if cpu.pc != 0x2000 {
+		t.Fatalf("pc = $%04X, want $2000 (IRQ vector)", cpu.pc)
+	}
+	if !cpu.pf.Test(I) {
+		t.Error("I flag not set after entering the IRQ handler")
+	}
+	if cpu.intMask.Test(IRQ) {
+		t.Error("IRQ still pending after being serviced")
+	}
+}
+
+// TestTriggerNMINotMaskable checks that TriggerNMI fires even with the I
+// flag set, unlike TriggerIRQ.
+func TestTriggerNMINotMaskable(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0xFFFA] = 0x00
+	mem.mem[0xFFFB] = 0x30
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.pf.Set(I)
+	cpu.TriggerNMI()
+
+	cpu.Step()
+	if cpu.pc != 0x3000 {
+		t.Fatalf("pc = $%04X, want $3000 (NMI vector)", cpu.pc)
+	}
+	if cpu.intMask.Test(NMI) {
+		t.Error("NMI still pending after being serviced")
+	}
+}
+
+// TestBRKAndRTI checks that BRK pushes PC+2 (skipping the signature byte)
+// with B set only in the pushed copy of the flags, and that RTI restores PC
+// from the stack and leaves the stack pointer where it was before BRK.
+func TestBRKAndRTI(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x00 // BRK
+	mem.mem[0x0001] = 0xFF // signature byte, skipped over
+	mem.mem[0xFFFE] = 0x00
+	mem.mem[0xFFFF] = 0x40
+	mem.mem[0x4000] = 0x40 // RTI
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.sp = 0xFF
+
+	cpu.Step() // BRK
+
+	if cpu.pc != 0x4000 {
+		t.Fatalf("pc = $%04X after BRK, want $4000 (IRQ vector)", cpu.pc)
+	}
+	if cpu.pf.Test(B) {
+		t.Error("B flag set in cpu.pf after BRK, want it only in the pushed copy")
+	}
+
+	var pushedFlags = ProgramFlags(mem.mem[Make16(1, 0xFD)])
+	if !pushedFlags.Test(B) {
+		t.Error("B flag not set in the flags BRK pushed to the stack")
+	}
+
+	var returnLo = mem.mem[Make16(1, 0xFE)]
+	var returnHi = mem.mem[Make16(1, 0xFF)]
+	if Make16(returnHi, returnLo) != 0x0002 {
+		t.Errorf("pushed return address = $%04X, want $0002 (PC+2)", Make16(returnHi, returnLo))
+	}
+
+	cpu.Step() // RTI
+
+	if cpu.pc != 0x0002 {
+		t.Errorf("pc = $%04X after RTI, want $0002", cpu.pc)
+	}
+	if cpu.sp != 0xFF {
+		t.Errorf("sp = $%02X after RTI, want $FF (stack balanced)", cpu.sp)
+	}
+}
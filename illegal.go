@@ -0,0 +1,494 @@
+package go6502
+
+import . "github.com/coopersimon/go6502/flags"
+
+// This file holds the commonly relied-upon "illegal" (undocumented) NMOS
+// opcodes: combination read-modify-write instructions, the unstable
+// AND-derived immediate opcodes, and the various widths of do-nothing NOP.
+//
+// cmosOpcodes inherits these same table entries from buildNMOSOpcodes() for
+// every slot it doesn't explicitly give a real 65C02 instruction to. Since a
+// CMOS CPU's illegalOpcodes field defaults to false, they fall back to their
+// built-in same-byte-count NOP behavior automatically — the 65C02 never
+// executes the NMOS side effect, only the addressing mode's byte/cycle cost.
+
+// addIllegalOpcodes adds the undocumented NMOS opcodes to an NMOS opcode
+// table. Each one with a side effect checks cpu.illegalOpcodes at run time
+// and falls back to behaving as a same-size NOP when it's disabled; the JAM
+// opcodes are the exception and halt the CPU either way.
+func addIllegalOpcodes(t *[256]opcodeFn) {
+	// LAX: LDA+LDX combined.
+	t[0xA3] = func(cpu *CPU) { cpu.lax((*CPU).indexedIndirect) }
+	t[0xA7] = func(cpu *CPU) { cpu.lax((*CPU).zeroPage) }
+	t[0xAF] = func(cpu *CPU) { cpu.lax((*CPU).absolute) }
+	t[0xB3] = func(cpu *CPU) { cpu.lax((*CPU).indirectIndexed) }
+	t[0xB7] = func(cpu *CPU) { cpu.lax((*CPU).zeroPageY) }
+	t[0xBF] = func(cpu *CPU) { cpu.lax((*CPU).absoluteY) }
+
+	// SAX: store A AND X.
+	t[0x83] = func(cpu *CPU) { cpu.sax((*CPU).indexedIndirect) }
+	t[0x87] = func(cpu *CPU) { cpu.sax((*CPU).zeroPage) }
+	t[0x8F] = func(cpu *CPU) { cpu.sax((*CPU).absolute) }
+	t[0x97] = func(cpu *CPU) { cpu.sax((*CPU).zeroPageY) }
+
+	// DCP: DEC then CMP.
+	t[0xC3] = func(cpu *CPU) { cpu.dcp((*CPU).indexedIndirect) }
+	t[0xC7] = func(cpu *CPU) { cpu.dcp((*CPU).zeroPage) }
+	t[0xCF] = func(cpu *CPU) { cpu.dcp((*CPU).absolute) }
+	t[0xD3] = func(cpu *CPU) { cpu.dcp((*CPU).indirectIndexed) }
+	t[0xD7] = func(cpu *CPU) { cpu.dcp((*CPU).zeroPageX) }
+	t[0xDB] = func(cpu *CPU) { cpu.dcp((*CPU).absoluteY) }
+	t[0xDF] = func(cpu *CPU) { cpu.dcp((*CPU).absoluteX) }
+
+	// ISC: INC then SBC.
+	t[0xE3] = func(cpu *CPU) { cpu.isc((*CPU).indexedIndirect) }
+	t[0xE7] = func(cpu *CPU) { cpu.isc((*CPU).zeroPage) }
+	t[0xEF] = func(cpu *CPU) { cpu.isc((*CPU).absolute) }
+	t[0xF3] = func(cpu *CPU) { cpu.isc((*CPU).indirectIndexed) }
+	t[0xF7] = func(cpu *CPU) { cpu.isc((*CPU).zeroPageX) }
+	t[0xFB] = func(cpu *CPU) { cpu.isc((*CPU).absoluteY) }
+	t[0xFF] = func(cpu *CPU) { cpu.isc((*CPU).absoluteX) }
+
+	// SLO: ASL then ORA.
+	t[0x03] = func(cpu *CPU) { cpu.slo((*CPU).indexedIndirect) }
+	t[0x07] = func(cpu *CPU) { cpu.slo((*CPU).zeroPage) }
+	t[0x0F] = func(cpu *CPU) { cpu.slo((*CPU).absolute) }
+	t[0x13] = func(cpu *CPU) { cpu.slo((*CPU).indirectIndexed) }
+	t[0x17] = func(cpu *CPU) { cpu.slo((*CPU).zeroPageX) }
+	t[0x1B] = func(cpu *CPU) { cpu.slo((*CPU).absoluteY) }
+	t[0x1F] = func(cpu *CPU) { cpu.slo((*CPU).absoluteX) }
+
+	// RLA: ROL then AND.
+	t[0x23] = func(cpu *CPU) { cpu.rla((*CPU).indexedIndirect) }
+	t[0x27] = func(cpu *CPU) { cpu.rla((*CPU).zeroPage) }
+	t[0x2F] = func(cpu *CPU) { cpu.rla((*CPU).absolute) }
+	t[0x33] = func(cpu *CPU) { cpu.rla((*CPU).indirectIndexed) }
+	t[0x37] = func(cpu *CPU) { cpu.rla((*CPU).zeroPageX) }
+	t[0x3B] = func(cpu *CPU) { cpu.rla((*CPU).absoluteY) }
+	t[0x3F] = func(cpu *CPU) { cpu.rla((*CPU).absoluteX) }
+
+	// SRE: LSR then EOR.
+	t[0x43] = func(cpu *CPU) { cpu.sre((*CPU).indexedIndirect) }
+	t[0x47] = func(cpu *CPU) { cpu.sre((*CPU).zeroPage) }
+	t[0x4F] = func(cpu *CPU) { cpu.sre((*CPU).absolute) }
+	t[0x53] = func(cpu *CPU) { cpu.sre((*CPU).indirectIndexed) }
+	t[0x57] = func(cpu *CPU) { cpu.sre((*CPU).zeroPageX) }
+	t[0x5B] = func(cpu *CPU) { cpu.sre((*CPU).absoluteY) }
+	t[0x5F] = func(cpu *CPU) { cpu.sre((*CPU).absoluteX) }
+
+	// RRA: ROR then ADC.
+	t[0x63] = func(cpu *CPU) { cpu.rra((*CPU).indexedIndirect) }
+	t[0x67] = func(cpu *CPU) { cpu.rra((*CPU).zeroPage) }
+	t[0x6F] = func(cpu *CPU) { cpu.rra((*CPU).absolute) }
+	t[0x73] = func(cpu *CPU) { cpu.rra((*CPU).indirectIndexed) }
+	t[0x77] = func(cpu *CPU) { cpu.rra((*CPU).zeroPageX) }
+	t[0x7B] = func(cpu *CPU) { cpu.rra((*CPU).absoluteY) }
+	t[0x7F] = func(cpu *CPU) { cpu.rra((*CPU).absoluteX) }
+
+	// ANC, ALR, ARR: unstable AND-derived immediate opcodes.
+	t[0x0B] = func(cpu *CPU) { cpu.anc() }
+	t[0x2B] = func(cpu *CPU) { cpu.anc() }
+	t[0x4B] = func(cpu *CPU) { cpu.alr() }
+	t[0x6B] = func(cpu *CPU) { cpu.arr() }
+
+	// AXS (SBX): immediate, fully deterministic unlike its neighbors above.
+	t[0xCB] = func(cpu *CPU) { cpu.axs() }
+
+	// EB is an undocumented duplicate of SBC #imm.
+	t[0xEB] = func(cpu *CPU) {
+		if cpu.illegalOpcodes {
+			cpu.sbc(nil)
+		} else {
+			cpu.nopImmediate()
+		}
+	}
+
+	// ANE, LXA, SHA, SHY, SHX, TAS: real silicon's output here depends on
+	// internal bus capacitance that varies chip to chip, so there's no
+	// single "correct" value to reproduce. Treat them as same-size,
+	// side-effect-free reads/writes instead.
+	t[0x8B] = func(cpu *CPU) { cpu.nopImmediate() }                     // ANE (XAA)
+	t[0xAB] = func(cpu *CPU) { cpu.nopImmediate() }                     // LXA (ATX)
+	t[0x93] = func(cpu *CPU) { cpu.nopDiscard((*CPU).indirectIndexed) } // SHA (AHX), (zp),Y
+	t[0x9F] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absoluteY) }       // SHA (AHX), absolute,Y
+	t[0x9C] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absoluteX) }       // SHY
+	t[0x9E] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absoluteY) }       // SHX
+	t[0x9B] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absoluteY) }       // TAS (SHS)
+
+	// LAS (LAE): unlike its unstable neighbors above, this one is fully
+	// deterministic.
+	t[0xBB] = func(cpu *CPU) { cpu.las() }
+
+	// JAM: real NMOS hardware locks the bus on these opcodes and never
+	// fetches again without a reset. Model that with the same halt line
+	// Reset's caller-visible halt state already uses, rather than crashing
+	// the host process on a nil opcode entry.
+	for _, col := range [...]uint8{0x02, 0x12, 0x22, 0x32, 0x42, 0x52, 0x62, 0x72, 0x92, 0xB2, 0xD2, 0xF2} {
+		t[col] = func(cpu *CPU) { cpu.halt = true }
+	}
+
+	// Safe 1-byte NOPs.
+	for _, col := range [...]uint8{0x1A, 0x3A, 0x5A, 0x7A, 0xDA, 0xFA} {
+		t[col] = func(cpu *CPU) {}
+	}
+
+	// 2-byte immediate NOPs.
+	for _, col := range [...]uint8{0x80, 0x82, 0x89, 0xC2, 0xE2} {
+		t[col] = func(cpu *CPU) { cpu.nopImmediate() }
+	}
+
+	// 2-byte zero page NOPs.
+	t[0x04] = func(cpu *CPU) { cpu.nopDiscard((*CPU).zeroPage) }
+	t[0x44] = func(cpu *CPU) { cpu.nopDiscard((*CPU).zeroPage) }
+	t[0x64] = func(cpu *CPU) { cpu.nopDiscard((*CPU).zeroPage) }
+
+	// 2-byte zero page,X NOPs.
+	for _, col := range [...]uint8{0x14, 0x34, 0x54, 0x74, 0xD4, 0xF4} {
+		t[col] = func(cpu *CPU) { cpu.nopDiscard((*CPU).zeroPageX) }
+	}
+
+	// 3-byte absolute NOP.
+	t[0x0C] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absolute) }
+
+	// 3-byte absolute,X NOPs.
+	for _, col := range [...]uint8{0x1C, 0x3C, 0x5C, 0x7C, 0xDC, 0xFC} {
+		t[col] = func(cpu *CPU) { cpu.nopDiscard((*CPU).absoluteX) }
+	}
+}
+
+// addIllegalCycles adds the base cycle counts for the opcodes added by
+// addIllegalOpcodes, matching real NMOS hardware regardless of whether
+// cpu.illegalOpcodes is enabled (disabling it changes behavior, not timing).
+func addIllegalCycles(t *[256]uint8) {
+	// LAX: same shape as LDA, but with zeroPageY/absoluteY instead of
+	// zeroPageX/... for the (zp),Y-adjacent forms.
+	t[0xA3] = 6
+	t[0xA7] = 3
+	t[0xAF] = 4
+	t[0xB3] = 5
+	t[0xB7] = 4
+	t[0xBF] = 4
+
+	// SAX: same shape as STA/STX, no page-crossing penalty.
+	t[0x83] = 6
+	t[0x87] = 3
+	t[0x8F] = 4
+	t[0x97] = 4
+
+	// DCP, ISC, SLO, RLA, SRE, RRA: RMW combos, the same cost as the
+	// corresponding legal RMW instruction where one exists (zp, zpX, abs,
+	// absX), and also defined for absolute,Y and the indirect addressing
+	// modes that legal RMW instructions don't use.
+	for _, col := range [...]uint8{0x03, 0x23, 0x43, 0x63, 0xC3, 0xE3} {
+		t[col] = 8
+	}
+	for _, col := range [...]uint8{0x07, 0x27, 0x47, 0x67, 0xC7, 0xE7} {
+		t[col] = 5
+	}
+	for _, col := range [...]uint8{0x0F, 0x2F, 0x4F, 0x6F, 0xCF, 0xEF} {
+		t[col] = 6
+	}
+	for _, col := range [...]uint8{0x13, 0x33, 0x53, 0x73, 0xD3, 0xF3} {
+		t[col] = 8
+	}
+	for _, col := range [...]uint8{0x17, 0x37, 0x57, 0x77, 0xD7, 0xF7} {
+		t[col] = 6
+	}
+	for _, col := range [...]uint8{0x1B, 0x3B, 0x5B, 0x7B, 0xDB, 0xFB} {
+		t[col] = 7
+	}
+	for _, col := range [...]uint8{0x1F, 0x3F, 0x5F, 0x7F, 0xDF, 0xFF} {
+		t[col] = 7
+	}
+
+	// ANC, ALR, ARR: immediate, 2 cycles.
+	t[0x0B] = 2
+	t[0x2B] = 2
+	t[0x4B] = 2
+	t[0x6B] = 2
+
+	// AXS: immediate, 2 cycles. EB: undocumented SBC #imm duplicate, same
+	// cost as the real thing.
+	t[0xCB] = 2
+	t[0xEB] = 2
+
+	// ANE, LXA: immediate, 2 cycles.
+	t[0x8B] = 2
+	t[0xAB] = 2
+
+	// SHA, TAS, SHY, SHX: same cost as their addressing mode would normally
+	// charge.
+	t[0x93] = 6
+	t[0x9F] = 5
+	t[0x9C] = 5
+	t[0x9E] = 5
+	t[0x9B] = 5
+
+	// LAS: absolute,Y, same base cost as the other absolute,Y opcodes above.
+	t[0xBB] = 4
+
+	// JAM: the opcode fetch itself is the only cycle that happens before
+	// the bus locks up.
+	for _, col := range [...]uint8{0x02, 0x12, 0x22, 0x32, 0x42, 0x52, 0x62, 0x72, 0x92, 0xB2, 0xD2, 0xF2} {
+		t[col] = 1
+	}
+
+	// Safe 1-byte NOPs.
+	for _, col := range [...]uint8{0x1A, 0x3A, 0x5A, 0x7A, 0xDA, 0xFA} {
+		t[col] = 2
+	}
+
+	// 2-byte immediate NOPs.
+	for _, col := range [...]uint8{0x80, 0x82, 0x89, 0xC2, 0xE2} {
+		t[col] = 2
+	}
+
+	// 2-byte zero page NOPs.
+	t[0x04] = 3
+	t[0x44] = 3
+	t[0x64] = 3
+
+	// 2-byte zero page,X NOPs.
+	for _, col := range [...]uint8{0x14, 0x34, 0x54, 0x74, 0xD4, 0xF4} {
+		t[col] = 4
+	}
+
+	// 3-byte absolute NOP.
+	t[0x0C] = 4
+
+	// 3-byte absolute,X NOPs.
+	for _, col := range [...]uint8{0x1C, 0x3C, 0x5C, 0x7C, 0xDC, 0xFC} {
+		t[col] = 4
+	}
+}
+
+/*** Instructions ***/
+
+// lax loads data into both A and X (LDA+LDX combined).
+func (cpu *CPU) lax(addrMode addrModeReadFn) {
+	data, _, crossed := cpu.dataAddr(addrMode)
+	cpu.chargeCrossing(crossed)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.setNZ(data)
+	cpu.acc = data
+	cpu.x = data
+}
+
+// sax stores A AND X, without touching any flags.
+func (cpu *CPU) sax(addrMode addrModeReadFn) {
+	addr, _ := addrMode(cpu)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.memWrite(addr, cpu.acc&cpu.x)
+}
+
+// dcp decrements memory, then compares the result against A (DEC+CMP).
+func (cpu *CPU) dcp(addrMode addrModeReadFn) {
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var result = data - 1
+	cpu.rmwWrite(addr, data, result)
+	cpu.setNZ(cpu.acc - result)
+	cpu.pf.SetIf(C, cpu.acc >= result)
+}
+
+// isc increments memory, then subtracts the result from A (INC+SBC).
+func (cpu *CPU) isc(addrMode addrModeReadFn) {
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var result = data + 1
+	cpu.rmwWrite(addr, data, result)
+
+	if cpu.pf.Test(D) {
+		cpu.decimalSub(result)
+	} else {
+		cpu.binaryArithmetic(^result)
+	}
+}
+
+// slo shifts memory left, then ORs the result into A (ASL+ORA).
+func (cpu *CPU) slo(addrMode addrModeReadFn) {
+	const highBit = 1 << 7
+
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var result = data << 1
+	cpu.pf.SetIf(C, (data&highBit) != 0)
+	cpu.rmwWrite(addr, data, result)
+
+	cpu.acc |= result
+	cpu.setNZ(cpu.acc)
+}
+
+// rla rotates memory left, then ANDs the result into A (ROL+AND).
+func (cpu *CPU) rla(addrMode addrModeReadFn) {
+	const highBit = 1 << 7
+
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var carry = uint8(cpu.pf & C)
+	var result = (data << 1) | carry
+	cpu.pf.SetIf(C, (data&highBit) != 0)
+	cpu.rmwWrite(addr, data, result)
+
+	cpu.acc &= result
+	cpu.setNZ(cpu.acc)
+}
+
+// sre shifts memory right, then EORs the result into A (LSR+EOR).
+func (cpu *CPU) sre(addrMode addrModeReadFn) {
+	const lowBit = 1 << 0
+
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var result = data >> 1
+	cpu.pf.SetIf(C, (data&lowBit) != 0)
+	cpu.rmwWrite(addr, data, result)
+
+	cpu.acc ^= result
+	cpu.setNZ(cpu.acc)
+}
+
+// rra rotates memory right, then adds the result into A (ROR+ADC).
+func (cpu *CPU) rra(addrMode addrModeReadFn) {
+	const lowBit = 1 << 0
+
+	data, addr, _ := cpu.dataAddr(addrMode)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var carry = uint8(cpu.pf&C) << 7
+	var result = (data >> 1) | carry
+	cpu.pf.SetIf(C, (data&lowBit) != 0)
+	cpu.rmwWrite(addr, data, result)
+
+	if cpu.pf.Test(D) {
+		cpu.decimalAdd(result)
+	} else {
+		cpu.binaryArithmetic(result)
+	}
+}
+
+// anc ANDs the immediate operand into A, then copies the result's sign bit
+// into C, as if the AND had fed an ASL.
+func (cpu *CPU) anc() {
+	data := cpu.fetch()
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.acc &= data
+	cpu.setNZ(cpu.acc)
+	cpu.pf.SetIf(C, cpu.pf.Test(N))
+}
+
+// alr ANDs the immediate operand into A, then logical-shifts A right (AND+LSR).
+func (cpu *CPU) alr() {
+	const lowBit = 1 << 0
+
+	data := cpu.fetch()
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.acc &= data
+	cpu.pf.SetIf(C, (cpu.acc&lowBit) != 0)
+	cpu.acc >>= 1
+	cpu.setNZ(cpu.acc)
+}
+
+// arr ANDs the immediate operand into A, then rotates A right through carry
+// (AND+ROR); C and V come from bits 6 and 5 of the rotated result rather than
+// from the rotate itself, a well known hardware quirk of this opcode.
+func (cpu *CPU) arr() {
+	data := cpu.fetch()
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.acc &= data
+
+	var carry = uint8(cpu.pf&C) << 7
+	cpu.acc = (cpu.acc >> 1) | carry
+	cpu.setNZ(cpu.acc)
+
+	cpu.pf.SetIf(C, (cpu.acc&(1<<6)) != 0)
+	cpu.pf.SetIf(V, ((cpu.acc>>6)^(cpu.acc>>5))&1 != 0)
+}
+
+// axs (also known as SBX) ANDs A and X, then subtracts the immediate
+// operand from that without involving the carry flag — like CMP, not SBC.
+// The difference replaces X; C, Z, and N come out as CMP's would.
+func (cpu *CPU) axs() {
+	data, _, _ := cpu.dataAddr(nil)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	var masked = cpu.acc & cpu.x
+	cpu.pf.SetIf(C, masked >= data)
+	cpu.x = masked - data
+	cpu.setNZ(cpu.x)
+}
+
+// las (also known as LAE) ANDs memory with SP, then loads the result into A,
+// X, and SP all at once.
+func (cpu *CPU) las() {
+	data, _, crossed := cpu.dataAddr((*CPU).absoluteY)
+	cpu.chargeCrossing(crossed)
+
+	if !cpu.illegalOpcodes {
+		return
+	}
+
+	cpu.sp &= data
+	cpu.acc = cpu.sp
+	cpu.x = cpu.sp
+	cpu.setNZ(cpu.sp)
+}
+
+// nopImmediate discards a 2-byte immediate-addressed NOP's operand byte.
+func (cpu *CPU) nopImmediate() {
+	cpu.fetch()
+}
+
+// nopDiscard resolves addrMode purely to consume the right number of operand
+// bytes (and the page-crossing cycle, whenever addrMode reports a crossing:
+// absolute,X, absolute,Y, or ($xx),Y), without reading or writing memory.
+func (cpu *CPU) nopDiscard(addrMode addrModeReadFn) {
+	_, crossed := addrMode(cpu)
+	cpu.chargeCrossing(crossed)
+}
@@ -0,0 +1,165 @@
+package go6502
+
+import (
+	"testing"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// TestNoNilOpcodeSlots checks that every one of the 256 opcode slots in both
+// variants' dispatch tables is populated, so that executing any byte as an
+// opcode — a corrupted ROM, a wild jump into data, a deliberate JAM byte —
+// never panics on a nil opcodeFn.
+func TestNoNilOpcodeSlots(t *testing.T) {
+	var mem flatMemory
+
+	for i := 0; i < 256; i++ {
+		mem.mem[0] = byte(i)
+		mem.mem[1] = 0xEA
+
+		var cpu = NewNMOS6502(&mem)
+		cpu.Step()
+	}
+
+	for i := 0; i < 256; i++ {
+		mem.mem[0] = byte(i)
+		mem.mem[1] = 0xEA
+
+		var cpu = NewCMOS65C02(&mem)
+		cpu.Step()
+	}
+}
+
+// TestJamHaltsNMOS checks that an NMOS JAM opcode halts the CPU instead of
+// executing anything further, matching the real hardware's locked bus.
+func TestJamHaltsNMOS(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x02 // JAM
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+
+	// The Step that fetches and runs the JAM opcode itself still completes
+	// normally; it's every Step after that which finds the CPU halted.
+	if _, reason := cpu.Step(); reason != StopNormal {
+		t.Fatalf("Step() reason = %v, want StopNormal for the step that hits JAM", reason)
+	}
+
+	var pc = cpu.pc
+	if _, reason := cpu.Step(); reason != StopHalt {
+		t.Errorf("Step() reason = %v, want StopHalt to persist", reason)
+	}
+	if cpu.pc != pc {
+		t.Errorf("pc advanced from $%04X to $%04X while halted", pc, cpu.pc)
+	}
+}
+
+// TestJamIgnoresInterrupts checks that a halted CPU stays halted even when
+// an interrupt is pending, matching real hardware where only a reset can
+// clear a JAM.
+func TestJamIgnoresInterrupts(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x02 // JAM
+	mem.mem[0xFFFE] = 0x00
+	mem.mem[0xFFFF] = 0x20
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+
+	cpu.Step() // runs the JAM, sets halt
+	cpu.TriggerIRQ()
+	cpu.pf.Clear(I)
+
+	if _, reason := cpu.Step(); reason != StopHalt {
+		t.Errorf("Step() reason = %v, want StopHalt even with IRQ pending", reason)
+	}
+	if cpu.pc == 0x2000 {
+		t.Error("pc jumped to the IRQ vector while halted, want it to stay locked up")
+	}
+}
+
+// TestResetClearsHalt checks that Reset recovers a JAM-halted CPU, the one
+// thing real hardware's reset line can undo.
+func TestResetClearsHalt(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x02 // JAM
+	mem.mem[0xFFFC] = 0x00
+	mem.mem[0xFFFD] = 0x10
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.Step() // runs the JAM, sets halt
+
+	cpu.Reset()
+
+	if _, reason := cpu.Step(); reason != StopNormal {
+		t.Errorf("Step() reason = %v after Reset, want StopNormal (halt cleared)", reason)
+	}
+}
+
+// TestJamDoesNotHaltCMOS checks that the 65C02 never jams: the same opcode
+// byte that halts NMOS executes as a harmless NOP instead.
+func TestJamDoesNotHaltCMOS(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0x02
+	mem.mem[0x0001] = 0xFF
+
+	var cpu = NewCMOS65C02(&mem)
+	cpu.pc = 0x0000
+
+	if _, reason := cpu.Step(); reason != StopNormal {
+		t.Fatalf("Step() reason = %v, want StopNormal", reason)
+	}
+	if cpu.pc != 0x0002 {
+		t.Errorf("pc = $%04X, want $0002", cpu.pc)
+	}
+}
+
+// TestAXS checks SBX/AXS: X = (A AND X) - immediate, with C/Z/N set as a
+// CMP's would be, not SBC's.
+func TestAXS(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0xCB // AXS #$05
+	mem.mem[0x0001] = 0x05
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.acc = 0x0F
+	cpu.x = 0xFF
+
+	cpu.Step()
+
+	if cpu.x != 0x0A {
+		t.Errorf("x = $%02X, want $0A", cpu.x)
+	}
+	if !cpu.pf.Test(C) {
+		t.Error("C not set, want set (no borrow)")
+	}
+}
+
+// TestLAS checks that LAS ANDs memory with SP and loads the result into A,
+// X, and SP together, unlike its genuinely chip-dependent neighbors.
+func TestLAS(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0xBB // LAS $1000,Y
+	mem.mem[0x0001] = 0x00
+	mem.mem[0x0002] = 0x10
+	mem.mem[0x1005] = 0xF0
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.y = 0x05
+	cpu.sp = 0xFF
+
+	cpu.Step()
+
+	if cpu.sp != 0xF0 {
+		t.Errorf("sp = $%02X, want $F0", cpu.sp)
+	}
+	if cpu.acc != 0xF0 {
+		t.Errorf("acc = $%02X, want $F0", cpu.acc)
+	}
+	if cpu.x != 0xF0 {
+		t.Errorf("x = $%02X, want $F0", cpu.x)
+	}
+}
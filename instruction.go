@@ -0,0 +1,82 @@
+package go6502
+
+import "fmt"
+
+// Instruction is a decoded instruction: its address, raw encoding, and a
+// rendered mnemonic/operand pair. It's the shared representation used by
+// the disasm package and the CPU's own tracing hook, so the two render
+// instructions the same way. Decode, and so the disasm package, always uses
+// the NMOS table, while a 65C02 CPU's tracing hook decodes with the CMOS
+// table; for opcodes the 65C02 redefines the two can differ.
+type Instruction struct {
+	PC       uint16
+	Bytes    []byte
+	Mnemonic string
+	Operand  string
+	Size     int
+}
+
+// Decode reads and decodes the single instruction at pc from bus, using the
+// NMOS opcode table.
+func Decode(bus MemoryBus, pc uint16) Instruction {
+	return decodeWith(&NMOSOpcodeInfo, bus, pc)
+}
+
+func decodeWith(table *[256]OpcodeInfo, bus MemoryBus, pc uint16) Instruction {
+	var info = table[bus.Read(pc)]
+	var size = int(info.Size)
+
+	var bytes = make([]byte, size)
+	for i := 0; i < size; i++ {
+		bytes[i] = bus.Read(pc + uint16(i))
+	}
+
+	return Instruction{
+		PC:       pc,
+		Bytes:    bytes,
+		Mnemonic: info.Mnemonic,
+		Operand:  formatOperand(info.Mode, pc, bytes),
+		Size:     size,
+	}
+}
+
+// formatOperand renders an instruction's operand bytes according to its
+// addressing mode, in the conventional 6502 assembly syntax.
+func formatOperand(mode AddrMode, pc uint16, bytes []byte) string {
+	switch mode {
+	case Implied:
+		return ""
+	case Accumulator:
+		return "A"
+	case Immediate:
+		return fmt.Sprintf("#$%02X", bytes[1])
+	case ZeroPage:
+		return fmt.Sprintf("$%02X", bytes[1])
+	case ZeroPageX:
+		return fmt.Sprintf("$%02X,X", bytes[1])
+	case ZeroPageY:
+		return fmt.Sprintf("$%02X,Y", bytes[1])
+	case Absolute:
+		return fmt.Sprintf("$%04X", Make16(bytes[2], bytes[1]))
+	case AbsoluteX:
+		return fmt.Sprintf("$%04X,X", Make16(bytes[2], bytes[1]))
+	case AbsoluteY:
+		return fmt.Sprintf("$%04X,Y", Make16(bytes[2], bytes[1]))
+	case IndexedIndirect:
+		return fmt.Sprintf("($%02X,X)", bytes[1])
+	case IndirectIndexed:
+		return fmt.Sprintf("($%02X),Y", bytes[1])
+	case ZeroPageIndirect:
+		return fmt.Sprintf("($%02X)", bytes[1])
+	case Relative:
+		var target = pc + uint16(len(bytes)) + uint16(int8(bytes[1]))
+		return fmt.Sprintf("$%04X", target)
+	case Indirect:
+		return fmt.Sprintf("($%04X)", Make16(bytes[2], bytes[1]))
+	case ZeroPageRelative:
+		var target = pc + uint16(len(bytes)) + uint16(int8(bytes[2]))
+		return fmt.Sprintf("$%02X,$%04X", bytes[1], target)
+	default:
+		return ""
+	}
+}
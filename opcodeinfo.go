@@ -0,0 +1,566 @@
+package go6502
+
+// AddrMode identifies one of the 6502/65C02 addressing modes, for use by
+// tools (disassemblers, debuggers) that need to know how to decode and
+// render an instruction's operand without re-implementing the CPU's
+// addressing mode logic.
+type AddrMode uint8
+
+const (
+	Implied AddrMode = iota
+	Accumulator
+	Immediate
+	ZeroPage
+	ZeroPageX
+	ZeroPageY
+	Absolute
+	AbsoluteX
+	AbsoluteY
+	IndexedIndirect  // ($xx,X)
+	IndirectIndexed  // ($xx),Y
+	ZeroPageIndirect // ($xx) — CMOS only
+	Relative
+	Indirect         // ($xxxx) — JMP only
+	ZeroPageRelative // $xx,rel — CMOS BBRn/BBSn only
+)
+
+// OpcodeInfo describes the static shape of an opcode: its mnemonic,
+// addressing mode, and encoded size in bytes. It doesn't carry any cycle
+// timing, since that's already covered by cycleTable and varies by whether
+// the addressing mode crosses a page.
+type OpcodeInfo struct {
+	Mnemonic string
+	Mode     AddrMode
+	Size     uint8
+}
+
+// NMOSOpcodeInfo is the opcode metadata table for the original NMOS 6502,
+// including the undocumented opcodes handled by illegal.go. The JAM slots,
+// which halt the CPU, are "???", Implied, size 1.
+//
+// This table is kept in sync with nmosOpcodes and nmosCycles by hand; it
+// exists so that tools like the disasm package can decode instructions
+// without duplicating the CPU's own dispatch tables.
+var NMOSOpcodeInfo = [256]OpcodeInfo{
+	0x00: {Mnemonic: "BRK", Mode: Implied, Size: 1},
+	0x01: {Mnemonic: "ORA", Mode: IndexedIndirect, Size: 2},
+	0x02: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x03: {Mnemonic: "SLO", Mode: IndexedIndirect, Size: 2},
+	0x04: {Mnemonic: "NOP", Mode: ZeroPage, Size: 2},
+	0x05: {Mnemonic: "ORA", Mode: ZeroPage, Size: 2},
+	0x06: {Mnemonic: "ASL", Mode: ZeroPage, Size: 2},
+	0x07: {Mnemonic: "SLO", Mode: ZeroPage, Size: 2},
+	0x08: {Mnemonic: "PHP", Mode: Implied, Size: 1},
+	0x09: {Mnemonic: "ORA", Mode: Immediate, Size: 2},
+	0x0A: {Mnemonic: "ASL", Mode: Accumulator, Size: 1},
+	0x0B: {Mnemonic: "ANC", Mode: Immediate, Size: 2},
+	0x0C: {Mnemonic: "NOP", Mode: Absolute, Size: 3},
+	0x0D: {Mnemonic: "ORA", Mode: Absolute, Size: 3},
+	0x0E: {Mnemonic: "ASL", Mode: Absolute, Size: 3},
+	0x0F: {Mnemonic: "SLO", Mode: Absolute, Size: 3},
+	0x10: {Mnemonic: "BPL", Mode: Relative, Size: 2},
+	0x11: {Mnemonic: "ORA", Mode: IndirectIndexed, Size: 2},
+	0x12: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x13: {Mnemonic: "SLO", Mode: IndirectIndexed, Size: 2},
+	0x14: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x15: {Mnemonic: "ORA", Mode: ZeroPageX, Size: 2},
+	0x16: {Mnemonic: "ASL", Mode: ZeroPageX, Size: 2},
+	0x17: {Mnemonic: "SLO", Mode: ZeroPageX, Size: 2},
+	0x18: {Mnemonic: "CLC", Mode: Implied, Size: 1},
+	0x19: {Mnemonic: "ORA", Mode: AbsoluteY, Size: 3},
+	0x1A: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0x1B: {Mnemonic: "SLO", Mode: AbsoluteY, Size: 3},
+	0x1C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x1D: {Mnemonic: "ORA", Mode: AbsoluteX, Size: 3},
+	0x1E: {Mnemonic: "ASL", Mode: AbsoluteX, Size: 3},
+	0x1F: {Mnemonic: "SLO", Mode: AbsoluteX, Size: 3},
+	0x20: {Mnemonic: "JSR", Mode: Absolute, Size: 3},
+	0x21: {Mnemonic: "AND", Mode: IndexedIndirect, Size: 2},
+	0x22: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x23: {Mnemonic: "RLA", Mode: IndexedIndirect, Size: 2},
+	0x24: {Mnemonic: "BIT", Mode: ZeroPage, Size: 2},
+	0x25: {Mnemonic: "AND", Mode: ZeroPage, Size: 2},
+	0x26: {Mnemonic: "ROL", Mode: ZeroPage, Size: 2},
+	0x27: {Mnemonic: "RLA", Mode: ZeroPage, Size: 2},
+	0x28: {Mnemonic: "PLP", Mode: Implied, Size: 1},
+	0x29: {Mnemonic: "AND", Mode: Immediate, Size: 2},
+	0x2A: {Mnemonic: "ROL", Mode: Accumulator, Size: 1},
+	0x2B: {Mnemonic: "ANC", Mode: Immediate, Size: 2},
+	0x2C: {Mnemonic: "BIT", Mode: Absolute, Size: 3},
+	0x2D: {Mnemonic: "AND", Mode: Absolute, Size: 3},
+	0x2E: {Mnemonic: "ROL", Mode: Absolute, Size: 3},
+	0x2F: {Mnemonic: "RLA", Mode: Absolute, Size: 3},
+	0x30: {Mnemonic: "BMI", Mode: Relative, Size: 2},
+	0x31: {Mnemonic: "AND", Mode: IndirectIndexed, Size: 2},
+	0x32: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x33: {Mnemonic: "RLA", Mode: IndirectIndexed, Size: 2},
+	0x34: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x35: {Mnemonic: "AND", Mode: ZeroPageX, Size: 2},
+	0x36: {Mnemonic: "ROL", Mode: ZeroPageX, Size: 2},
+	0x37: {Mnemonic: "RLA", Mode: ZeroPageX, Size: 2},
+	0x38: {Mnemonic: "SEC", Mode: Implied, Size: 1},
+	0x39: {Mnemonic: "AND", Mode: AbsoluteY, Size: 3},
+	0x3A: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0x3B: {Mnemonic: "RLA", Mode: AbsoluteY, Size: 3},
+	0x3C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x3D: {Mnemonic: "AND", Mode: AbsoluteX, Size: 3},
+	0x3E: {Mnemonic: "ROL", Mode: AbsoluteX, Size: 3},
+	0x3F: {Mnemonic: "RLA", Mode: AbsoluteX, Size: 3},
+	0x40: {Mnemonic: "RTI", Mode: Implied, Size: 1},
+	0x41: {Mnemonic: "EOR", Mode: IndexedIndirect, Size: 2},
+	0x42: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x43: {Mnemonic: "SRE", Mode: IndexedIndirect, Size: 2},
+	0x44: {Mnemonic: "NOP", Mode: ZeroPage, Size: 2},
+	0x45: {Mnemonic: "EOR", Mode: ZeroPage, Size: 2},
+	0x46: {Mnemonic: "LSR", Mode: ZeroPage, Size: 2},
+	0x47: {Mnemonic: "SRE", Mode: ZeroPage, Size: 2},
+	0x48: {Mnemonic: "PHA", Mode: Implied, Size: 1},
+	0x49: {Mnemonic: "EOR", Mode: Immediate, Size: 2},
+	0x4A: {Mnemonic: "LSR", Mode: Accumulator, Size: 1},
+	0x4B: {Mnemonic: "ALR", Mode: Immediate, Size: 2},
+	0x4C: {Mnemonic: "JMP", Mode: Absolute, Size: 3},
+	0x4D: {Mnemonic: "EOR", Mode: Absolute, Size: 3},
+	0x4E: {Mnemonic: "LSR", Mode: Absolute, Size: 3},
+	0x4F: {Mnemonic: "SRE", Mode: Absolute, Size: 3},
+	0x50: {Mnemonic: "BVC", Mode: Relative, Size: 2},
+	0x51: {Mnemonic: "EOR", Mode: IndirectIndexed, Size: 2},
+	0x52: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x53: {Mnemonic: "SRE", Mode: IndirectIndexed, Size: 2},
+	0x54: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x55: {Mnemonic: "EOR", Mode: ZeroPageX, Size: 2},
+	0x56: {Mnemonic: "LSR", Mode: ZeroPageX, Size: 2},
+	0x57: {Mnemonic: "SRE", Mode: ZeroPageX, Size: 2},
+	0x58: {Mnemonic: "CLI", Mode: Implied, Size: 1},
+	0x59: {Mnemonic: "EOR", Mode: AbsoluteY, Size: 3},
+	0x5A: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0x5B: {Mnemonic: "SRE", Mode: AbsoluteY, Size: 3},
+	0x5C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x5D: {Mnemonic: "EOR", Mode: AbsoluteX, Size: 3},
+	0x5E: {Mnemonic: "LSR", Mode: AbsoluteX, Size: 3},
+	0x5F: {Mnemonic: "SRE", Mode: AbsoluteX, Size: 3},
+	0x60: {Mnemonic: "RTS", Mode: Implied, Size: 1},
+	0x61: {Mnemonic: "ADC", Mode: IndexedIndirect, Size: 2},
+	0x62: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x63: {Mnemonic: "RRA", Mode: IndexedIndirect, Size: 2},
+	0x64: {Mnemonic: "NOP", Mode: ZeroPage, Size: 2},
+	0x65: {Mnemonic: "ADC", Mode: ZeroPage, Size: 2},
+	0x66: {Mnemonic: "ROR", Mode: ZeroPage, Size: 2},
+	0x67: {Mnemonic: "RRA", Mode: ZeroPage, Size: 2},
+	0x68: {Mnemonic: "PLA", Mode: Implied, Size: 1},
+	0x69: {Mnemonic: "ADC", Mode: Immediate, Size: 2},
+	0x6A: {Mnemonic: "ROR", Mode: Accumulator, Size: 1},
+	0x6B: {Mnemonic: "ARR", Mode: Immediate, Size: 2},
+	0x6C: {Mnemonic: "JMP", Mode: Indirect, Size: 3},
+	0x6D: {Mnemonic: "ADC", Mode: Absolute, Size: 3},
+	0x6E: {Mnemonic: "ROR", Mode: Absolute, Size: 3},
+	0x6F: {Mnemonic: "RRA", Mode: Absolute, Size: 3},
+	0x70: {Mnemonic: "BVS", Mode: Relative, Size: 2},
+	0x71: {Mnemonic: "ADC", Mode: IndirectIndexed, Size: 2},
+	0x72: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x73: {Mnemonic: "RRA", Mode: IndirectIndexed, Size: 2},
+	0x74: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x75: {Mnemonic: "ADC", Mode: ZeroPageX, Size: 2},
+	0x76: {Mnemonic: "ROR", Mode: ZeroPageX, Size: 2},
+	0x77: {Mnemonic: "RRA", Mode: ZeroPageX, Size: 2},
+	0x78: {Mnemonic: "SEI", Mode: Implied, Size: 1},
+	0x79: {Mnemonic: "ADC", Mode: AbsoluteY, Size: 3},
+	0x7A: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0x7B: {Mnemonic: "RRA", Mode: AbsoluteY, Size: 3},
+	0x7C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x7D: {Mnemonic: "ADC", Mode: AbsoluteX, Size: 3},
+	0x7E: {Mnemonic: "ROR", Mode: AbsoluteX, Size: 3},
+	0x7F: {Mnemonic: "RRA", Mode: AbsoluteX, Size: 3},
+	0x80: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x81: {Mnemonic: "STA", Mode: IndexedIndirect, Size: 2},
+	0x82: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x83: {Mnemonic: "SAX", Mode: IndexedIndirect, Size: 2},
+	0x84: {Mnemonic: "STY", Mode: ZeroPage, Size: 2},
+	0x85: {Mnemonic: "STA", Mode: ZeroPage, Size: 2},
+	0x86: {Mnemonic: "STX", Mode: ZeroPage, Size: 2},
+	0x87: {Mnemonic: "SAX", Mode: ZeroPage, Size: 2},
+	0x88: {Mnemonic: "DEY", Mode: Implied, Size: 1},
+	0x89: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x8A: {Mnemonic: "TXA", Mode: Implied, Size: 1},
+	0x8B: {Mnemonic: "ANE", Mode: Immediate, Size: 2},
+	0x8C: {Mnemonic: "STY", Mode: Absolute, Size: 3},
+	0x8D: {Mnemonic: "STA", Mode: Absolute, Size: 3},
+	0x8E: {Mnemonic: "STX", Mode: Absolute, Size: 3},
+	0x8F: {Mnemonic: "SAX", Mode: Absolute, Size: 3},
+	0x90: {Mnemonic: "BCC", Mode: Relative, Size: 2},
+	0x91: {Mnemonic: "STA", Mode: IndirectIndexed, Size: 2},
+	0x92: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0x93: {Mnemonic: "SHA", Mode: IndirectIndexed, Size: 2},
+	0x94: {Mnemonic: "STY", Mode: ZeroPageX, Size: 2},
+	0x95: {Mnemonic: "STA", Mode: ZeroPageX, Size: 2},
+	0x96: {Mnemonic: "STX", Mode: ZeroPageY, Size: 2},
+	0x97: {Mnemonic: "SAX", Mode: ZeroPageY, Size: 2},
+	0x98: {Mnemonic: "TYA", Mode: Implied, Size: 1},
+	0x99: {Mnemonic: "STA", Mode: AbsoluteY, Size: 3},
+	0x9A: {Mnemonic: "TXS", Mode: Implied, Size: 1},
+	0x9B: {Mnemonic: "TAS", Mode: AbsoluteY, Size: 3},
+	0x9C: {Mnemonic: "SHY", Mode: AbsoluteX, Size: 3},
+	0x9D: {Mnemonic: "STA", Mode: AbsoluteX, Size: 3},
+	0x9E: {Mnemonic: "SHX", Mode: AbsoluteY, Size: 3},
+	0x9F: {Mnemonic: "SHA", Mode: AbsoluteY, Size: 3},
+	0xA0: {Mnemonic: "LDY", Mode: Immediate, Size: 2},
+	0xA1: {Mnemonic: "LDA", Mode: IndexedIndirect, Size: 2},
+	0xA2: {Mnemonic: "LDX", Mode: Immediate, Size: 2},
+	0xA3: {Mnemonic: "LAX", Mode: IndexedIndirect, Size: 2},
+	0xA4: {Mnemonic: "LDY", Mode: ZeroPage, Size: 2},
+	0xA5: {Mnemonic: "LDA", Mode: ZeroPage, Size: 2},
+	0xA6: {Mnemonic: "LDX", Mode: ZeroPage, Size: 2},
+	0xA7: {Mnemonic: "LAX", Mode: ZeroPage, Size: 2},
+	0xA8: {Mnemonic: "TAY", Mode: Implied, Size: 1},
+	0xA9: {Mnemonic: "LDA", Mode: Immediate, Size: 2},
+	0xAA: {Mnemonic: "TAX", Mode: Implied, Size: 1},
+	0xAB: {Mnemonic: "LXA", Mode: Immediate, Size: 2},
+	0xAC: {Mnemonic: "LDY", Mode: Absolute, Size: 3},
+	0xAD: {Mnemonic: "LDA", Mode: Absolute, Size: 3},
+	0xAE: {Mnemonic: "LDX", Mode: Absolute, Size: 3},
+	0xAF: {Mnemonic: "LAX", Mode: Absolute, Size: 3},
+	0xB0: {Mnemonic: "BCS", Mode: Relative, Size: 2},
+	0xB1: {Mnemonic: "LDA", Mode: IndirectIndexed, Size: 2},
+	0xB2: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0xB3: {Mnemonic: "LAX", Mode: IndirectIndexed, Size: 2},
+	0xB4: {Mnemonic: "LDY", Mode: ZeroPageX, Size: 2},
+	0xB5: {Mnemonic: "LDA", Mode: ZeroPageX, Size: 2},
+	0xB6: {Mnemonic: "LDX", Mode: ZeroPageY, Size: 2},
+	0xB7: {Mnemonic: "LAX", Mode: ZeroPageY, Size: 2},
+	0xB8: {Mnemonic: "CLV", Mode: Implied, Size: 1},
+	0xB9: {Mnemonic: "LDA", Mode: AbsoluteY, Size: 3},
+	0xBA: {Mnemonic: "TSX", Mode: Implied, Size: 1},
+	0xBB: {Mnemonic: "LAS", Mode: AbsoluteY, Size: 3},
+	0xBC: {Mnemonic: "LDY", Mode: AbsoluteX, Size: 3},
+	0xBD: {Mnemonic: "LDA", Mode: AbsoluteX, Size: 3},
+	0xBE: {Mnemonic: "LDX", Mode: AbsoluteY, Size: 3},
+	0xBF: {Mnemonic: "LAX", Mode: AbsoluteY, Size: 3},
+	0xC0: {Mnemonic: "CPY", Mode: Immediate, Size: 2},
+	0xC1: {Mnemonic: "CMP", Mode: IndexedIndirect, Size: 2},
+	0xC2: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0xC3: {Mnemonic: "DCP", Mode: IndexedIndirect, Size: 2},
+	0xC4: {Mnemonic: "CPY", Mode: ZeroPage, Size: 2},
+	0xC5: {Mnemonic: "CMP", Mode: ZeroPage, Size: 2},
+	0xC6: {Mnemonic: "DEC", Mode: ZeroPage, Size: 2},
+	0xC7: {Mnemonic: "DCP", Mode: ZeroPage, Size: 2},
+	0xC8: {Mnemonic: "INY", Mode: Implied, Size: 1},
+	0xC9: {Mnemonic: "CMP", Mode: Immediate, Size: 2},
+	0xCA: {Mnemonic: "DEX", Mode: Implied, Size: 1},
+	0xCB: {Mnemonic: "AXS", Mode: Immediate, Size: 2},
+	0xCC: {Mnemonic: "CPY", Mode: Absolute, Size: 3},
+	0xCD: {Mnemonic: "CMP", Mode: Absolute, Size: 3},
+	0xCE: {Mnemonic: "DEC", Mode: Absolute, Size: 3},
+	0xCF: {Mnemonic: "DCP", Mode: Absolute, Size: 3},
+	0xD0: {Mnemonic: "BNE", Mode: Relative, Size: 2},
+	0xD1: {Mnemonic: "CMP", Mode: IndirectIndexed, Size: 2},
+	0xD2: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0xD3: {Mnemonic: "DCP", Mode: IndirectIndexed, Size: 2},
+	0xD4: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0xD5: {Mnemonic: "CMP", Mode: ZeroPageX, Size: 2},
+	0xD6: {Mnemonic: "DEC", Mode: ZeroPageX, Size: 2},
+	0xD7: {Mnemonic: "DCP", Mode: ZeroPageX, Size: 2},
+	0xD8: {Mnemonic: "CLD", Mode: Implied, Size: 1},
+	0xD9: {Mnemonic: "CMP", Mode: AbsoluteY, Size: 3},
+	0xDA: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0xDB: {Mnemonic: "DCP", Mode: AbsoluteY, Size: 3},
+	0xDC: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0xDD: {Mnemonic: "CMP", Mode: AbsoluteX, Size: 3},
+	0xDE: {Mnemonic: "DEC", Mode: AbsoluteX, Size: 3},
+	0xDF: {Mnemonic: "DCP", Mode: AbsoluteX, Size: 3},
+	0xE0: {Mnemonic: "CPX", Mode: Immediate, Size: 2},
+	0xE1: {Mnemonic: "SBC", Mode: IndexedIndirect, Size: 2},
+	0xE2: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0xE3: {Mnemonic: "ISC", Mode: IndexedIndirect, Size: 2},
+	0xE4: {Mnemonic: "CPX", Mode: ZeroPage, Size: 2},
+	0xE5: {Mnemonic: "SBC", Mode: ZeroPage, Size: 2},
+	0xE6: {Mnemonic: "INC", Mode: ZeroPage, Size: 2},
+	0xE7: {Mnemonic: "ISC", Mode: ZeroPage, Size: 2},
+	0xE8: {Mnemonic: "INX", Mode: Implied, Size: 1},
+	0xE9: {Mnemonic: "SBC", Mode: Immediate, Size: 2},
+	0xEA: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0xEB: {Mnemonic: "SBC", Mode: Immediate, Size: 2},
+	0xEC: {Mnemonic: "CPX", Mode: Absolute, Size: 3},
+	0xED: {Mnemonic: "SBC", Mode: Absolute, Size: 3},
+	0xEE: {Mnemonic: "INC", Mode: Absolute, Size: 3},
+	0xEF: {Mnemonic: "ISC", Mode: Absolute, Size: 3},
+	0xF0: {Mnemonic: "BEQ", Mode: Relative, Size: 2},
+	0xF1: {Mnemonic: "SBC", Mode: IndirectIndexed, Size: 2},
+	0xF2: {Mnemonic: "???", Mode: Implied, Size: 1},
+	0xF3: {Mnemonic: "ISC", Mode: IndirectIndexed, Size: 2},
+	0xF4: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0xF5: {Mnemonic: "SBC", Mode: ZeroPageX, Size: 2},
+	0xF6: {Mnemonic: "INC", Mode: ZeroPageX, Size: 2},
+	0xF7: {Mnemonic: "ISC", Mode: ZeroPageX, Size: 2},
+	0xF8: {Mnemonic: "SED", Mode: Implied, Size: 1},
+	0xF9: {Mnemonic: "SBC", Mode: AbsoluteY, Size: 3},
+	0xFA: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0xFB: {Mnemonic: "ISC", Mode: AbsoluteY, Size: 3},
+	0xFC: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0xFD: {Mnemonic: "SBC", Mode: AbsoluteX, Size: 3},
+	0xFE: {Mnemonic: "INC", Mode: AbsoluteX, Size: 3},
+	0xFF: {Mnemonic: "ISC", Mode: AbsoluteX, Size: 3},
+}
+
+// CMOSOpcodeInfo is the opcode metadata table for the 65C02 (CMOS) variant.
+// It matches cmosOpcodes: mostly the NMOS table, with the new instructions
+// and addressing modes cmos.go adds, and the NMOS JAM slots it doesn't
+// reuse shown as the 2-byte NOPs it runs them as. The illegal NMOS opcodes
+// keep their NMOS mnemonics and sizes here, although a CMOS CPU runs them as
+// same-size NOPs unless illegalOpcodes is enabled (see illegalOpcodes on CPU).
+var CMOSOpcodeInfo = [256]OpcodeInfo{
+	0x00: {Mnemonic: "BRK", Mode: Implied, Size: 1},
+	0x01: {Mnemonic: "ORA", Mode: IndexedIndirect, Size: 2},
+	0x02: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x03: {Mnemonic: "SLO", Mode: IndexedIndirect, Size: 2},
+	0x04: {Mnemonic: "TSB", Mode: ZeroPage, Size: 2},
+	0x05: {Mnemonic: "ORA", Mode: ZeroPage, Size: 2},
+	0x06: {Mnemonic: "ASL", Mode: ZeroPage, Size: 2},
+	0x07: {Mnemonic: "RMB0", Mode: ZeroPage, Size: 2},
+	0x08: {Mnemonic: "PHP", Mode: Implied, Size: 1},
+	0x09: {Mnemonic: "ORA", Mode: Immediate, Size: 2},
+	0x0A: {Mnemonic: "ASL", Mode: Accumulator, Size: 1},
+	0x0B: {Mnemonic: "ANC", Mode: Immediate, Size: 2},
+	0x0C: {Mnemonic: "TSB", Mode: Absolute, Size: 3},
+	0x0D: {Mnemonic: "ORA", Mode: Absolute, Size: 3},
+	0x0E: {Mnemonic: "ASL", Mode: Absolute, Size: 3},
+	0x0F: {Mnemonic: "BBR0", Mode: ZeroPageRelative, Size: 3},
+	0x10: {Mnemonic: "BPL", Mode: Relative, Size: 2},
+	0x11: {Mnemonic: "ORA", Mode: IndirectIndexed, Size: 2},
+	0x12: {Mnemonic: "ORA", Mode: ZeroPageIndirect, Size: 2},
+	0x13: {Mnemonic: "SLO", Mode: IndirectIndexed, Size: 2},
+	0x14: {Mnemonic: "TRB", Mode: ZeroPage, Size: 2},
+	0x15: {Mnemonic: "ORA", Mode: ZeroPageX, Size: 2},
+	0x16: {Mnemonic: "ASL", Mode: ZeroPageX, Size: 2},
+	0x17: {Mnemonic: "RMB1", Mode: ZeroPage, Size: 2},
+	0x18: {Mnemonic: "CLC", Mode: Implied, Size: 1},
+	0x19: {Mnemonic: "ORA", Mode: AbsoluteY, Size: 3},
+	0x1A: {Mnemonic: "INC", Mode: Accumulator, Size: 1},
+	0x1B: {Mnemonic: "SLO", Mode: AbsoluteY, Size: 3},
+	0x1C: {Mnemonic: "TRB", Mode: Absolute, Size: 3},
+	0x1D: {Mnemonic: "ORA", Mode: AbsoluteX, Size: 3},
+	0x1E: {Mnemonic: "ASL", Mode: AbsoluteX, Size: 3},
+	0x1F: {Mnemonic: "BBR1", Mode: ZeroPageRelative, Size: 3},
+	0x20: {Mnemonic: "JSR", Mode: Absolute, Size: 3},
+	0x21: {Mnemonic: "AND", Mode: IndexedIndirect, Size: 2},
+	0x22: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x23: {Mnemonic: "RLA", Mode: IndexedIndirect, Size: 2},
+	0x24: {Mnemonic: "BIT", Mode: ZeroPage, Size: 2},
+	0x25: {Mnemonic: "AND", Mode: ZeroPage, Size: 2},
+	0x26: {Mnemonic: "ROL", Mode: ZeroPage, Size: 2},
+	0x27: {Mnemonic: "RMB2", Mode: ZeroPage, Size: 2},
+	0x28: {Mnemonic: "PLP", Mode: Implied, Size: 1},
+	0x29: {Mnemonic: "AND", Mode: Immediate, Size: 2},
+	0x2A: {Mnemonic: "ROL", Mode: Accumulator, Size: 1},
+	0x2B: {Mnemonic: "ANC", Mode: Immediate, Size: 2},
+	0x2C: {Mnemonic: "BIT", Mode: Absolute, Size: 3},
+	0x2D: {Mnemonic: "AND", Mode: Absolute, Size: 3},
+	0x2E: {Mnemonic: "ROL", Mode: Absolute, Size: 3},
+	0x2F: {Mnemonic: "BBR2", Mode: ZeroPageRelative, Size: 3},
+	0x30: {Mnemonic: "BMI", Mode: Relative, Size: 2},
+	0x31: {Mnemonic: "AND", Mode: IndirectIndexed, Size: 2},
+	0x32: {Mnemonic: "AND", Mode: ZeroPageIndirect, Size: 2},
+	0x33: {Mnemonic: "RLA", Mode: IndirectIndexed, Size: 2},
+	0x34: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x35: {Mnemonic: "AND", Mode: ZeroPageX, Size: 2},
+	0x36: {Mnemonic: "ROL", Mode: ZeroPageX, Size: 2},
+	0x37: {Mnemonic: "RMB3", Mode: ZeroPage, Size: 2},
+	0x38: {Mnemonic: "SEC", Mode: Implied, Size: 1},
+	0x39: {Mnemonic: "AND", Mode: AbsoluteY, Size: 3},
+	0x3A: {Mnemonic: "DEC", Mode: Accumulator, Size: 1},
+	0x3B: {Mnemonic: "RLA", Mode: AbsoluteY, Size: 3},
+	0x3C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x3D: {Mnemonic: "AND", Mode: AbsoluteX, Size: 3},
+	0x3E: {Mnemonic: "ROL", Mode: AbsoluteX, Size: 3},
+	0x3F: {Mnemonic: "BBR3", Mode: ZeroPageRelative, Size: 3},
+	0x40: {Mnemonic: "RTI", Mode: Implied, Size: 1},
+	0x41: {Mnemonic: "EOR", Mode: IndexedIndirect, Size: 2},
+	0x42: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x43: {Mnemonic: "SRE", Mode: IndexedIndirect, Size: 2},
+	0x44: {Mnemonic: "NOP", Mode: ZeroPage, Size: 2},
+	0x45: {Mnemonic: "EOR", Mode: ZeroPage, Size: 2},
+	0x46: {Mnemonic: "LSR", Mode: ZeroPage, Size: 2},
+	0x47: {Mnemonic: "RMB4", Mode: ZeroPage, Size: 2},
+	0x48: {Mnemonic: "PHA", Mode: Implied, Size: 1},
+	0x49: {Mnemonic: "EOR", Mode: Immediate, Size: 2},
+	0x4A: {Mnemonic: "LSR", Mode: Accumulator, Size: 1},
+	0x4B: {Mnemonic: "ALR", Mode: Immediate, Size: 2},
+	0x4C: {Mnemonic: "JMP", Mode: Absolute, Size: 3},
+	0x4D: {Mnemonic: "EOR", Mode: Absolute, Size: 3},
+	0x4E: {Mnemonic: "LSR", Mode: Absolute, Size: 3},
+	0x4F: {Mnemonic: "BBR4", Mode: ZeroPageRelative, Size: 3},
+	0x50: {Mnemonic: "BVC", Mode: Relative, Size: 2},
+	0x51: {Mnemonic: "EOR", Mode: IndirectIndexed, Size: 2},
+	0x52: {Mnemonic: "EOR", Mode: ZeroPageIndirect, Size: 2},
+	0x53: {Mnemonic: "SRE", Mode: IndirectIndexed, Size: 2},
+	0x54: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0x55: {Mnemonic: "EOR", Mode: ZeroPageX, Size: 2},
+	0x56: {Mnemonic: "LSR", Mode: ZeroPageX, Size: 2},
+	0x57: {Mnemonic: "RMB5", Mode: ZeroPage, Size: 2},
+	0x58: {Mnemonic: "CLI", Mode: Implied, Size: 1},
+	0x59: {Mnemonic: "EOR", Mode: AbsoluteY, Size: 3},
+	0x5A: {Mnemonic: "PHY", Mode: Implied, Size: 1},
+	0x5B: {Mnemonic: "SRE", Mode: AbsoluteY, Size: 3},
+	0x5C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x5D: {Mnemonic: "EOR", Mode: AbsoluteX, Size: 3},
+	0x5E: {Mnemonic: "LSR", Mode: AbsoluteX, Size: 3},
+	0x5F: {Mnemonic: "BBR5", Mode: ZeroPageRelative, Size: 3},
+	0x60: {Mnemonic: "RTS", Mode: Implied, Size: 1},
+	0x61: {Mnemonic: "ADC", Mode: IndexedIndirect, Size: 2},
+	0x62: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x63: {Mnemonic: "RRA", Mode: IndexedIndirect, Size: 2},
+	0x64: {Mnemonic: "STZ", Mode: ZeroPage, Size: 2},
+	0x65: {Mnemonic: "ADC", Mode: ZeroPage, Size: 2},
+	0x66: {Mnemonic: "ROR", Mode: ZeroPage, Size: 2},
+	0x67: {Mnemonic: "RMB6", Mode: ZeroPage, Size: 2},
+	0x68: {Mnemonic: "PLA", Mode: Implied, Size: 1},
+	0x69: {Mnemonic: "ADC", Mode: Immediate, Size: 2},
+	0x6A: {Mnemonic: "ROR", Mode: Accumulator, Size: 1},
+	0x6B: {Mnemonic: "ARR", Mode: Immediate, Size: 2},
+	0x6C: {Mnemonic: "JMP", Mode: Indirect, Size: 3},
+	0x6D: {Mnemonic: "ADC", Mode: Absolute, Size: 3},
+	0x6E: {Mnemonic: "ROR", Mode: Absolute, Size: 3},
+	0x6F: {Mnemonic: "BBR6", Mode: ZeroPageRelative, Size: 3},
+	0x70: {Mnemonic: "BVS", Mode: Relative, Size: 2},
+	0x71: {Mnemonic: "ADC", Mode: IndirectIndexed, Size: 2},
+	0x72: {Mnemonic: "ADC", Mode: ZeroPageIndirect, Size: 2},
+	0x73: {Mnemonic: "RRA", Mode: IndirectIndexed, Size: 2},
+	0x74: {Mnemonic: "STZ", Mode: ZeroPageX, Size: 2},
+	0x75: {Mnemonic: "ADC", Mode: ZeroPageX, Size: 2},
+	0x76: {Mnemonic: "ROR", Mode: ZeroPageX, Size: 2},
+	0x77: {Mnemonic: "RMB7", Mode: ZeroPage, Size: 2},
+	0x78: {Mnemonic: "SEI", Mode: Implied, Size: 1},
+	0x79: {Mnemonic: "ADC", Mode: AbsoluteY, Size: 3},
+	0x7A: {Mnemonic: "PLY", Mode: Implied, Size: 1},
+	0x7B: {Mnemonic: "RRA", Mode: AbsoluteY, Size: 3},
+	0x7C: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0x7D: {Mnemonic: "ADC", Mode: AbsoluteX, Size: 3},
+	0x7E: {Mnemonic: "ROR", Mode: AbsoluteX, Size: 3},
+	0x7F: {Mnemonic: "BBR7", Mode: ZeroPageRelative, Size: 3},
+	0x80: {Mnemonic: "BRA", Mode: Relative, Size: 2},
+	0x81: {Mnemonic: "STA", Mode: IndexedIndirect, Size: 2},
+	0x82: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x83: {Mnemonic: "SAX", Mode: IndexedIndirect, Size: 2},
+	0x84: {Mnemonic: "STY", Mode: ZeroPage, Size: 2},
+	0x85: {Mnemonic: "STA", Mode: ZeroPage, Size: 2},
+	0x86: {Mnemonic: "STX", Mode: ZeroPage, Size: 2},
+	0x87: {Mnemonic: "SMB0", Mode: ZeroPage, Size: 2},
+	0x88: {Mnemonic: "DEY", Mode: Implied, Size: 1},
+	0x89: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0x8A: {Mnemonic: "TXA", Mode: Implied, Size: 1},
+	0x8B: {Mnemonic: "ANE", Mode: Immediate, Size: 2},
+	0x8C: {Mnemonic: "STY", Mode: Absolute, Size: 3},
+	0x8D: {Mnemonic: "STA", Mode: Absolute, Size: 3},
+	0x8E: {Mnemonic: "STX", Mode: Absolute, Size: 3},
+	0x8F: {Mnemonic: "BBS0", Mode: ZeroPageRelative, Size: 3},
+	0x90: {Mnemonic: "BCC", Mode: Relative, Size: 2},
+	0x91: {Mnemonic: "STA", Mode: IndirectIndexed, Size: 2},
+	0x92: {Mnemonic: "STA", Mode: ZeroPageIndirect, Size: 2},
+	0x93: {Mnemonic: "SHA", Mode: IndirectIndexed, Size: 2},
+	0x94: {Mnemonic: "STY", Mode: ZeroPageX, Size: 2},
+	0x95: {Mnemonic: "STA", Mode: ZeroPageX, Size: 2},
+	0x96: {Mnemonic: "STX", Mode: ZeroPageY, Size: 2},
+	0x97: {Mnemonic: "SMB1", Mode: ZeroPage, Size: 2},
+	0x98: {Mnemonic: "TYA", Mode: Implied, Size: 1},
+	0x99: {Mnemonic: "STA", Mode: AbsoluteY, Size: 3},
+	0x9A: {Mnemonic: "TXS", Mode: Implied, Size: 1},
+	0x9B: {Mnemonic: "TAS", Mode: AbsoluteY, Size: 3},
+	0x9C: {Mnemonic: "STZ", Mode: Absolute, Size: 3},
+	0x9D: {Mnemonic: "STA", Mode: AbsoluteX, Size: 3},
+	0x9E: {Mnemonic: "STZ", Mode: AbsoluteX, Size: 3},
+	0x9F: {Mnemonic: "BBS1", Mode: ZeroPageRelative, Size: 3},
+	0xA0: {Mnemonic: "LDY", Mode: Immediate, Size: 2},
+	0xA1: {Mnemonic: "LDA", Mode: IndexedIndirect, Size: 2},
+	0xA2: {Mnemonic: "LDX", Mode: Immediate, Size: 2},
+	0xA3: {Mnemonic: "LAX", Mode: IndexedIndirect, Size: 2},
+	0xA4: {Mnemonic: "LDY", Mode: ZeroPage, Size: 2},
+	0xA5: {Mnemonic: "LDA", Mode: ZeroPage, Size: 2},
+	0xA6: {Mnemonic: "LDX", Mode: ZeroPage, Size: 2},
+	0xA7: {Mnemonic: "SMB2", Mode: ZeroPage, Size: 2},
+	0xA8: {Mnemonic: "TAY", Mode: Implied, Size: 1},
+	0xA9: {Mnemonic: "LDA", Mode: Immediate, Size: 2},
+	0xAA: {Mnemonic: "TAX", Mode: Implied, Size: 1},
+	0xAB: {Mnemonic: "LXA", Mode: Immediate, Size: 2},
+	0xAC: {Mnemonic: "LDY", Mode: Absolute, Size: 3},
+	0xAD: {Mnemonic: "LDA", Mode: Absolute, Size: 3},
+	0xAE: {Mnemonic: "LDX", Mode: Absolute, Size: 3},
+	0xAF: {Mnemonic: "BBS2", Mode: ZeroPageRelative, Size: 3},
+	0xB0: {Mnemonic: "BCS", Mode: Relative, Size: 2},
+	0xB1: {Mnemonic: "LDA", Mode: IndirectIndexed, Size: 2},
+	0xB2: {Mnemonic: "LDA", Mode: ZeroPageIndirect, Size: 2},
+	0xB3: {Mnemonic: "LAX", Mode: IndirectIndexed, Size: 2},
+	0xB4: {Mnemonic: "LDY", Mode: ZeroPageX, Size: 2},
+	0xB5: {Mnemonic: "LDA", Mode: ZeroPageX, Size: 2},
+	0xB6: {Mnemonic: "LDX", Mode: ZeroPageY, Size: 2},
+	0xB7: {Mnemonic: "SMB3", Mode: ZeroPage, Size: 2},
+	0xB8: {Mnemonic: "CLV", Mode: Implied, Size: 1},
+	0xB9: {Mnemonic: "LDA", Mode: AbsoluteY, Size: 3},
+	0xBA: {Mnemonic: "TSX", Mode: Implied, Size: 1},
+	0xBB: {Mnemonic: "LAS", Mode: AbsoluteY, Size: 3},
+	0xBC: {Mnemonic: "LDY", Mode: AbsoluteX, Size: 3},
+	0xBD: {Mnemonic: "LDA", Mode: AbsoluteX, Size: 3},
+	0xBE: {Mnemonic: "LDX", Mode: AbsoluteY, Size: 3},
+	0xBF: {Mnemonic: "BBS3", Mode: ZeroPageRelative, Size: 3},
+	0xC0: {Mnemonic: "CPY", Mode: Immediate, Size: 2},
+	0xC1: {Mnemonic: "CMP", Mode: IndexedIndirect, Size: 2},
+	0xC2: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0xC3: {Mnemonic: "DCP", Mode: IndexedIndirect, Size: 2},
+	0xC4: {Mnemonic: "CPY", Mode: ZeroPage, Size: 2},
+	0xC5: {Mnemonic: "CMP", Mode: ZeroPage, Size: 2},
+	0xC6: {Mnemonic: "DEC", Mode: ZeroPage, Size: 2},
+	0xC7: {Mnemonic: "SMB4", Mode: ZeroPage, Size: 2},
+	0xC8: {Mnemonic: "INY", Mode: Implied, Size: 1},
+	0xC9: {Mnemonic: "CMP", Mode: Immediate, Size: 2},
+	0xCA: {Mnemonic: "DEX", Mode: Implied, Size: 1},
+	0xCB: {Mnemonic: "AXS", Mode: Immediate, Size: 2},
+	0xCC: {Mnemonic: "CPY", Mode: Absolute, Size: 3},
+	0xCD: {Mnemonic: "CMP", Mode: Absolute, Size: 3},
+	0xCE: {Mnemonic: "DEC", Mode: Absolute, Size: 3},
+	0xCF: {Mnemonic: "BBS4", Mode: ZeroPageRelative, Size: 3},
+	0xD0: {Mnemonic: "BNE", Mode: Relative, Size: 2},
+	0xD1: {Mnemonic: "CMP", Mode: IndirectIndexed, Size: 2},
+	0xD2: {Mnemonic: "CMP", Mode: ZeroPageIndirect, Size: 2},
+	0xD3: {Mnemonic: "DCP", Mode: IndirectIndexed, Size: 2},
+	0xD4: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0xD5: {Mnemonic: "CMP", Mode: ZeroPageX, Size: 2},
+	0xD6: {Mnemonic: "DEC", Mode: ZeroPageX, Size: 2},
+	0xD7: {Mnemonic: "SMB5", Mode: ZeroPage, Size: 2},
+	0xD8: {Mnemonic: "CLD", Mode: Implied, Size: 1},
+	0xD9: {Mnemonic: "CMP", Mode: AbsoluteY, Size: 3},
+	0xDA: {Mnemonic: "PHX", Mode: Implied, Size: 1},
+	0xDB: {Mnemonic: "DCP", Mode: AbsoluteY, Size: 3},
+	0xDC: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0xDD: {Mnemonic: "CMP", Mode: AbsoluteX, Size: 3},
+	0xDE: {Mnemonic: "DEC", Mode: AbsoluteX, Size: 3},
+	0xDF: {Mnemonic: "BBS5", Mode: ZeroPageRelative, Size: 3},
+	0xE0: {Mnemonic: "CPX", Mode: Immediate, Size: 2},
+	0xE1: {Mnemonic: "SBC", Mode: IndexedIndirect, Size: 2},
+	0xE2: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0xE3: {Mnemonic: "ISC", Mode: IndexedIndirect, Size: 2},
+	0xE4: {Mnemonic: "CPX", Mode: ZeroPage, Size: 2},
+	0xE5: {Mnemonic: "SBC", Mode: ZeroPage, Size: 2},
+	0xE6: {Mnemonic: "INC", Mode: ZeroPage, Size: 2},
+	0xE7: {Mnemonic: "SMB6", Mode: ZeroPage, Size: 2},
+	0xE8: {Mnemonic: "INX", Mode: Implied, Size: 1},
+	0xE9: {Mnemonic: "SBC", Mode: Immediate, Size: 2},
+	0xEA: {Mnemonic: "NOP", Mode: Implied, Size: 1},
+	0xEB: {Mnemonic: "NOP", Mode: Immediate, Size: 2},
+	0xEC: {Mnemonic: "CPX", Mode: Absolute, Size: 3},
+	0xED: {Mnemonic: "SBC", Mode: Absolute, Size: 3},
+	0xEE: {Mnemonic: "INC", Mode: Absolute, Size: 3},
+	0xEF: {Mnemonic: "BBS6", Mode: ZeroPageRelative, Size: 3},
+	0xF0: {Mnemonic: "BEQ", Mode: Relative, Size: 2},
+	0xF1: {Mnemonic: "SBC", Mode: IndirectIndexed, Size: 2},
+	0xF2: {Mnemonic: "SBC", Mode: ZeroPageIndirect, Size: 2},
+	0xF3: {Mnemonic: "ISC", Mode: IndirectIndexed, Size: 2},
+	0xF4: {Mnemonic: "NOP", Mode: ZeroPageX, Size: 2},
+	0xF5: {Mnemonic: "SBC", Mode: ZeroPageX, Size: 2},
+	0xF6: {Mnemonic: "INC", Mode: ZeroPageX, Size: 2},
+	0xF7: {Mnemonic: "SMB7", Mode: ZeroPage, Size: 2},
+	0xF8: {Mnemonic: "SED", Mode: Implied, Size: 1},
+	0xF9: {Mnemonic: "SBC", Mode: AbsoluteY, Size: 3},
+	0xFA: {Mnemonic: "PLX", Mode: Implied, Size: 1},
+	0xFB: {Mnemonic: "ISC", Mode: AbsoluteY, Size: 3},
+	0xFC: {Mnemonic: "NOP", Mode: AbsoluteX, Size: 3},
+	0xFD: {Mnemonic: "SBC", Mode: AbsoluteX, Size: 3},
+	0xFE: {Mnemonic: "INC", Mode: AbsoluteX, Size: 3},
+	0xFF: {Mnemonic: "BBS7", Mode: ZeroPageRelative, Size: 3},
+}
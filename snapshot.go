@@ -0,0 +1,154 @@
+package go6502
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// snapshotVersion identifies the layout Save/Load read and write. Bump it
+// whenever a field is added, removed, or reordered, and give Load an
+// explicit reason to reject an older version rather than silently
+// misreading it.
+const snapshotVersion uint8 = 1
+
+// Save writes the CPU's complete architectural state — registers, flags,
+// halt/interrupt state, and elapsed cycle count — to w as a versioned,
+// little-endian binary snapshot, tagged with the CPU variant (NMOS/CMOS) so
+// a save state always restores into a CPU built the same way it was saved
+// from.
+//
+// Save only covers the CPU itself: it knows nothing about the memory bus a
+// caller wired it up to, so a full machine snapshot also needs the caller
+// to serialize its own MemoryBus alongside this.
+func (cpu *CPU) Save(w io.Writer) error {
+	var variant uint8
+	if cpu.cmos {
+		variant = 1
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, snapshotVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, variant); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.acc); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.x); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.y); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.sp); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.pc); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint8(cpu.pf)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.halt); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint8(cpu.intMask)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cpu.totalCycles); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Load reads a snapshot written by Save, restoring the CPU's architectural
+// state in place. It rejects a snapshot from an incompatible format version
+// or a different CPU variant (NMOS/CMOS) than cpu was constructed with,
+// since the opcode and cycle tables a CPU uses come from how it was built,
+// not from the snapshot.
+func (cpu *CPU) Load(r io.Reader) error {
+	var version uint8
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return err
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("go6502: unsupported snapshot version %d", version)
+	}
+
+	var variant uint8
+	if err := binary.Read(r, binary.LittleEndian, &variant); err != nil {
+		return err
+	}
+	if (variant != 0) != cpu.cmos {
+		return fmt.Errorf("go6502: snapshot is for a different CPU variant (cmos=%v)", variant != 0)
+	}
+
+	var acc, x, y, sp uint8
+	var pc uint16
+	var pf uint8
+	var halt bool
+	var intMask uint8
+	var totalCycles uint64
+
+	if err := binary.Read(r, binary.LittleEndian, &acc); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &x); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &y); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &sp); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &pc); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &pf); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &halt); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &intMask); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &totalCycles); err != nil {
+		return err
+	}
+
+	cpu.acc = acc
+	cpu.x = x
+	cpu.y = y
+	cpu.sp = sp
+	cpu.pc = pc
+	cpu.pf = ProgramFlags(pf)
+	cpu.halt = halt
+	cpu.intMask = Interrupt(intMask)
+	cpu.totalCycles = totalCycles
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same
+// format Save writes.
+func (cpu *CPU) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cpu.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, reading the same
+// format Load expects.
+func (cpu *CPU) UnmarshalBinary(data []byte) error {
+	return cpu.Load(bytes.NewReader(data))
+}
@@ -0,0 +1,66 @@
+package go6502
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/coopersimon/go6502/flags"
+)
+
+// TestSnapshotRoundTrip checks that Save followed by Load on a fresh CPU of
+// the same variant restores every field Save covers.
+func TestSnapshotRoundTrip(t *testing.T) {
+	var mem flatMemory
+	var cpu = NewNMOS6502(&mem)
+
+	cpu.acc = 0x12
+	cpu.x = 0x34
+	cpu.y = 0x56
+	cpu.sp = 0x78
+	cpu.pc = 0xBEEF
+	cpu.pf.Set(C | Z | N)
+	cpu.halt = true
+	cpu.intMask.Set(NMI)
+	cpu.totalCycles = 123456789
+
+	var buf bytes.Buffer
+	if err := cpu.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	var restored = NewNMOS6502(&mem)
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	switch {
+	case restored.acc != cpu.acc,
+		restored.x != cpu.x,
+		restored.y != cpu.y,
+		restored.sp != cpu.sp,
+		restored.pc != cpu.pc,
+		restored.pf != cpu.pf,
+		restored.halt != cpu.halt,
+		restored.intMask != cpu.intMask,
+		restored.totalCycles != cpu.totalCycles:
+		t.Errorf("restored CPU = %+v, want %+v", *restored, *cpu)
+	}
+}
+
+// TestSnapshotRejectsVariantMismatch checks that Load refuses a snapshot
+// saved from the other CPU variant, since the opcode and cycle tables a CPU
+// uses come from how it was constructed, not from the snapshot.
+func TestSnapshotRejectsVariantMismatch(t *testing.T) {
+	var mem flatMemory
+	var nmos = NewNMOS6502(&mem)
+
+	var buf bytes.Buffer
+	if err := nmos.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	var cmos = NewCMOS65C02(&mem)
+	if err := cmos.Load(&buf); err == nil {
+		t.Error("Load into a CMOS CPU from an NMOS snapshot succeeded, want error")
+	}
+}
@@ -0,0 +1,97 @@
+package go6502
+
+import . "github.com/coopersimon/go6502/flags"
+
+// StateSnapshot captures the visible CPU state at a point in time, for a
+// Tracer to compare before and after a step.
+type StateSnapshot struct {
+	A, X, Y, SP uint8
+	PC          uint16
+	P           ProgramFlags
+	Cycles      uint64
+}
+
+func (cpu *CPU) snapshot() StateSnapshot {
+	return StateSnapshot{
+		A:      cpu.acc,
+		X:      cpu.x,
+		Y:      cpu.y,
+		SP:     cpu.sp,
+		PC:     cpu.pc,
+		P:      cpu.pf,
+		Cycles: cpu.totalCycles,
+	}
+}
+
+// Tracer observes every instruction Step executes, seeing the CPU state
+// immediately before and after, and the decoded instruction itself. It's
+// the hook a frontend uses to produce a Klaus-Dormann-style trace log or
+// feed a TUI debugger.
+type Tracer interface {
+	OnStep(pre, post StateSnapshot, instr Instruction)
+}
+
+// SetTracer installs t to observe every instruction Step executes. Passing
+// nil removes the current tracer. With no tracer installed, Step doesn't
+// decode the instruction or build a StateSnapshot at all.
+func (cpu *CPU) SetTracer(t Tracer) {
+	cpu.tracer = t
+}
+
+// SetBreakpoint stops Step before it executes the instruction at pc,
+// reporting StopBreakpoint instead.
+func (cpu *CPU) SetBreakpoint(pc uint16) {
+	if cpu.breakpoints == nil {
+		cpu.breakpoints = make(map[uint16]struct{})
+	}
+	cpu.breakpoints[pc] = struct{}{}
+}
+
+// ClearBreakpoint removes a breakpoint set with SetBreakpoint. Clearing an
+// address with no breakpoint is a no-op.
+func (cpu *CPU) ClearBreakpoint(pc uint16) {
+	delete(cpu.breakpoints, pc)
+}
+
+// WatchKind selects which accesses to a watched address should stop Step.
+type WatchKind uint8
+
+const (
+	WatchRead WatchKind = iota
+	WatchWrite
+	WatchAny
+)
+
+// SetWatchpoint stops Step just after it executes an instruction that
+// accesses addr in a way matching kind, reporting StopWatchpoint instead.
+func (cpu *CPU) SetWatchpoint(addr uint16, kind WatchKind) {
+	if cpu.watchpoints == nil {
+		cpu.watchpoints = make(map[uint16]WatchKind)
+	}
+	cpu.watchpoints[addr] = kind
+}
+
+// checkWatch records whether addr was accessed in a way matching its
+// watchpoint, if any. Reading from a nil map is safe and costs nothing
+// close to what installing a real watchpoint would, so this stays cheap
+// when no watchpoints are set.
+func (cpu *CPU) checkWatch(addr uint16, access WatchKind) {
+	if kind, watched := cpu.watchpoints[addr]; watched {
+		if kind == access || kind == WatchAny {
+			cpu.watchHit = true
+		}
+	}
+}
+
+// StopReason explains why Step stopped, beyond simply running one
+// instruction: a breakpoint at the instruction it was about to execute, a
+// watchpoint hit by the instruction it just executed, the CPU being
+// halted, or nothing notable at all.
+type StopReason uint8
+
+const (
+	StopNormal StopReason = iota
+	StopBreakpoint
+	StopWatchpoint
+	StopHalt
+)
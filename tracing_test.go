@@ -0,0 +1,113 @@
+package go6502
+
+import "testing"
+
+// TestBreakpointStopsBeforeExecuting checks that Step reports StopBreakpoint
+// instead of running the instruction at a breakpointed address.
+func TestBreakpointStopsBeforeExecuting(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0xA9 // LDA #$42
+	mem.mem[0x0001] = 0x42
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.SetBreakpoint(0x0000)
+
+	if _, reason := cpu.Step(); reason != StopBreakpoint {
+		t.Fatalf("Step() reason = %v, want StopBreakpoint", reason)
+	}
+	if cpu.acc != 0 {
+		t.Errorf("acc = $%02X, want $00 (LDA should not have run)", cpu.acc)
+	}
+
+	cpu.ClearBreakpoint(0x0000)
+	if _, reason := cpu.Step(); reason != StopNormal {
+		t.Fatalf("Step() reason = %v, want StopNormal after clearing breakpoint", reason)
+	}
+	if cpu.acc != 0x42 {
+		t.Errorf("acc = $%02X, want $42", cpu.acc)
+	}
+}
+
+// TestWatchpointStopsAfterAccess checks that Step reports StopWatchpoint
+// once an instruction accesses a watched address in a matching way.
+func TestWatchpointStopsAfterAccess(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0xA9 // LDA #$7F
+	mem.mem[0x0001] = 0x7F
+	mem.mem[0x0002] = 0x85 // STA $10
+	mem.mem[0x0003] = 0x10
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+	cpu.SetWatchpoint(0x0010, WatchWrite)
+
+	if _, reason := cpu.Step(); reason != StopNormal {
+		t.Fatalf("LDA Step() reason = %v, want StopNormal", reason)
+	}
+	if _, reason := cpu.Step(); reason != StopWatchpoint {
+		t.Fatalf("STA Step() reason = %v, want StopWatchpoint", reason)
+	}
+	if mem.mem[0x0010] != 0x7F {
+		t.Errorf("mem[$10] = $%02X, want $7F", mem.mem[0x0010])
+	}
+}
+
+// tracerLog records every OnStep call it receives, in order.
+type tracerLog struct {
+	steps []struct {
+		pre, post StateSnapshot
+		instr     Instruction
+	}
+}
+
+func (l *tracerLog) OnStep(pre, post StateSnapshot, instr Instruction) {
+	l.steps = append(l.steps, struct {
+		pre, post StateSnapshot
+		instr     Instruction
+	}{pre, post, instr})
+}
+
+// TestTracerObservesStep checks that a Tracer installed with SetTracer sees
+// the CPU state before and after each instruction, and the instruction that
+// ran between them.
+func TestTracerObservesStep(t *testing.T) {
+	var mem flatMemory
+	mem.mem[0x0000] = 0xA9 // LDA #$01
+	mem.mem[0x0001] = 0x01
+
+	var cpu = NewNMOS6502(&mem)
+	cpu.pc = 0x0000
+
+	var log tracerLog
+	cpu.SetTracer(&log)
+
+	cpu.Step()
+
+	if len(log.steps) != 1 {
+		t.Fatalf("got %d OnStep calls, want 1", len(log.steps))
+	}
+
+	var step = log.steps[0]
+	if step.pre.PC != 0x0000 {
+		t.Errorf("pre.PC = $%04X, want $0000", step.pre.PC)
+	}
+	if step.pre.A != 0 {
+		t.Errorf("pre.A = $%02X, want $00", step.pre.A)
+	}
+	if step.post.A != 0x01 {
+		t.Errorf("post.A = $%02X, want $01", step.post.A)
+	}
+	if step.post.PC != 0x0002 {
+		t.Errorf("post.PC = $%04X, want $0002", step.post.PC)
+	}
+	if step.instr.Mnemonic != "LDA" {
+		t.Errorf("instr.Mnemonic = %q, want %q", step.instr.Mnemonic, "LDA")
+	}
+
+	cpu.SetTracer(nil)
+	cpu.Step()
+	if len(log.steps) != 1 {
+		t.Errorf("got %d OnStep calls after clearing tracer, want still 1", len(log.steps))
+	}
+}